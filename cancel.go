@@ -0,0 +1,96 @@
+package fflow
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// CancelMode define como o ffmpeg é encerrado quando o contexto de Run,
+// RunWithProgress ou Cmd é cancelado.
+//
+// CancelMode defines how ffmpeg is stopped when the context of Run,
+// RunWithProgress or Cmd is cancelled.
+type CancelMode int
+
+const (
+	// CancelKill encerra o processo imediatamente (SIGKILL). É o padrão, mas pode deixar
+	// outputs incompletos, como MP4 sem o atom moov.
+	//
+	// CancelKill terminates the process immediately (SIGKILL). It is the default, but it may
+	// leave incomplete outputs, such as MP4 files without the moov atom.
+	CancelKill CancelMode = iota
+
+	// CancelQuit escreve "q" no stdin do ffmpeg, que finaliza os outputs e sai.
+	// Quando o stdin já é usado por InputReader, CancelInterrupt é usado no lugar.
+	// Não funciona com -nostdin.
+	//
+	// CancelQuit writes "q" to ffmpeg's stdin, which finalizes the outputs and exits.
+	// When stdin is already used by InputReader, CancelInterrupt is used instead.
+	// It does not work with -nostdin.
+	CancelQuit
+
+	// CancelInterrupt envia SIGINT ao ffmpeg, que também finaliza os outputs.
+	// Não é suportado no Windows, onde o processo é encerrado após o período de espera.
+	//
+	// CancelInterrupt sends SIGINT to ffmpeg, which also finalizes the outputs.
+	// It is not supported on Windows, where the process is killed after the grace period.
+	CancelInterrupt
+)
+
+// DefaultGracePeriod é o período de espera usado por WithGracefulCancel quando grace
+// não é positivo, para que um ffmpeg travado ainda seja encerrado à força.
+//
+// DefaultGracePeriod is the grace period used by WithGracefulCancel when grace
+// is not positive, so that a stuck ffmpeg is still killed.
+var DefaultGracePeriod = 10 * time.Second
+
+// WithGracefulCancel define como o ffmpeg é encerrado no cancelamento e quanto tempo
+// esperar (grace) antes de encerrá-lo à força. Com CancelQuit e CancelInterrupt, um
+// grace zero ou negativo usa DefaultGracePeriod. Se o ffmpeg sair com sucesso depois do
+// cancelamento, o erro retornado ainda envolve ctx.Err().
+//
+// WithGracefulCancel sets how ffmpeg is stopped on cancellation and how long to
+// wait (grace) before killing it. With CancelQuit and CancelInterrupt, a zero or
+// negative grace uses DefaultGracePeriod. If ffmpeg exits successfully after the
+// cancellation, the returned error still wraps ctx.Err().
+func WithGracefulCancel(mode CancelMode, grace time.Duration) Option {
+	if mode != CancelKill && grace <= 0 {
+		grace = DefaultGracePeriod
+	}
+	return func(b *ffmpegBuilder) {
+		b.cancelMode = mode
+		b.grace = grace
+	}
+}
+
+// setCancel configura exec.Cmd.Cancel e WaitDelay de acordo com spec.
+// Deve ser chamada antes de cmd.Start.
+//
+// setCancel configures exec.Cmd.Cancel and WaitDelay according to spec.
+// It must be called before cmd.Start.
+func setCancel(cmd *exec.Cmd, spec ExecSpec) {
+	mode := spec.CancelMode
+	if mode == CancelQuit && spec.Stdin != nil {
+		mode = CancelInterrupt
+	}
+
+	switch mode {
+	case CancelQuit:
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return
+		}
+		cmd.Cancel = func() error {
+			_, err := io.WriteString(stdin, "q")
+			_ = stdin.Close()
+			return err
+		}
+	case CancelInterrupt:
+		cmd.Cancel = func() error {
+			return cmd.Process.Signal(os.Interrupt)
+		}
+	}
+	cmd.WaitDelay = spec.GracePeriod
+}
@@ -0,0 +1,160 @@
+package fflow
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCancel simula o ffmpeg esperando o cancelamento: "quit" sai ao ler "q" do stdin,
+// "interrupt" sai ao receber SIGINT e "hang" ignora ambos.
+func fakeCancel(mode string) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	quit := make(chan struct{})
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if _, err := os.Stdin.Read(buf); err != nil {
+				return
+			}
+			if buf[0] == 'q' {
+				close(quit)
+				return
+			}
+		}
+	}()
+
+	_, _ = os.Stdout.WriteString("ready\n")
+	switch mode {
+	case "quit":
+		<-quit
+	case "interrupt":
+		<-interrupt
+	case "hang":
+		time.Sleep(time.Minute)
+	}
+	_, _ = os.Stdout.WriteString("finalized\n")
+}
+
+// readyWriter avisa quando o processo falso escreveu "ready".
+type readyWriter struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	once  sync.Once
+	ready chan struct{}
+}
+
+func (w *readyWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n, err := w.buf.Write(p)
+	if strings.Contains(w.buf.String(), "ready") {
+		w.once.Do(func() { close(w.ready) })
+	}
+	return n, err
+}
+
+func (w *readyWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestGracefulCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("o processo falso depende de sinais POSIX")
+	}
+
+	run := func(t *testing.T, mode string, cancel CancelMode, grace time.Duration, input io.Reader) (string, error, time.Duration) {
+		t.Helper()
+		out := &readyWriter{ready: make(chan struct{})}
+		var in beforeReadStage = New(
+			WithBinary(os.Args[0]),
+			WithEnv("FFLOW_FAKE_FFMPEG="+mode),
+			WithGracefulCancel(cancel, grace),
+		)
+
+		var read readStagee
+		if input != nil {
+			read = in.InputReader(input, "h264")
+		} else {
+			read = in.Input("in.mp4")
+		}
+		cmd := read.OutputWriter(out, "mp4").Command()
+
+		ctx, stop := context.WithCancel(context.Background())
+		go func() {
+			<-out.ready
+			stop()
+		}()
+
+		start := time.Now()
+		err := cmd.Run(ctx)
+		return out.String(), err, time.Since(start)
+	}
+
+	t.Run("CancelQuit escreve q no stdin", func(t *testing.T) {
+		out, err, _ := run(t, "quit", CancelQuit, 5*time.Second, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Contains(t, out, "finalized")
+	})
+
+	t.Run("CancelInterrupt envia SIGINT", func(t *testing.T) {
+		out, err, _ := run(t, "interrupt", CancelInterrupt, 5*time.Second, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Contains(t, out, "finalized")
+	})
+
+	t.Run("CancelQuit usa SIGINT quando o stdin é um input", func(t *testing.T) {
+		out, err, _ := run(t, "interrupt", CancelQuit, 5*time.Second, strings.NewReader("frames"))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Contains(t, out, "finalized")
+	})
+
+	t.Run("Encerra o processo após o período de espera", func(t *testing.T) {
+		out, err, elapsed := run(t, "hang", CancelQuit, 200*time.Millisecond, nil)
+		require.Error(t, err)
+		assert.NotContains(t, out, "finalized")
+		assert.Less(t, elapsed, 10*time.Second)
+	})
+
+	t.Run("Grace zero usa DefaultGracePeriod", func(t *testing.T) {
+		defer func(d time.Duration) { DefaultGracePeriod = d }(DefaultGracePeriod)
+		DefaultGracePeriod = 200 * time.Millisecond
+
+		out, err, elapsed := run(t, "hang", CancelQuit, 0, nil)
+		require.Error(t, err)
+		assert.NotContains(t, out, "finalized")
+		assert.Less(t, elapsed, 10*time.Second)
+
+		b := newBuilder(nil, WithGracefulCancel(CancelInterrupt, 0))
+		assert.Equal(t, 200*time.Millisecond, b.grace)
+
+		b = newBuilder(nil, WithGracefulCancel(CancelKill, 0))
+		assert.Zero(t, b.grace)
+	})
+
+	t.Run("ExecSpec carrega o modo de cancelamento", func(t *testing.T) {
+		fake := &fakeExecutor{}
+		err := New(WithExecutor(fake), WithGracefulCancel(CancelInterrupt, time.Second)).
+			Input("in.mp4").
+			Output("out.mp4").
+			Command().
+			Run(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, CancelInterrupt, fake.spec.CancelMode)
+		assert.Equal(t, time.Second, fake.spec.GracePeriod)
+	})
+}
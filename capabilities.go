@@ -0,0 +1,451 @@
+package fflow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Capabilities descreve o que o binário do ffmpeg instalado suporta, a partir de
+// -version, -encoders, -decoders, -filters, -formats, -hwaccels e -pix_fmts.
+//
+// Capabilities describes what the installed ffmpeg binary supports, based on
+// -version, -encoders, -decoders, -filters, -formats, -hwaccels and -pix_fmts.
+type Capabilities struct {
+	Version       string
+	Configuration []string
+	Encoders      map[string]CodecCapability
+	Decoders      map[string]CodecCapability
+	Filters       map[string]FilterCapability
+	Formats       map[string]FormatCapability
+	HWAccels      []string
+	PixFmts       map[string]PixFmtCapability
+}
+
+// CodecCapability descreve um encoder ou decoder.
+//
+// CodecCapability describes an encoder or decoder.
+type CodecCapability struct {
+	Name         string
+	Type         StreamType
+	Experimental bool
+	Description  string
+}
+
+// FilterCapability descreve um filtro. Inputs e Outputs usam a notação do ffmpeg
+// (V, A, N para dinâmico, | para source/sink), ex.: "VV" para overlay.
+//
+// FilterCapability describes a filter. Inputs and Outputs use ffmpeg notation
+// (V, A, N for dynamic, | for source/sink), e.g. "VV" for overlay.
+type FilterCapability struct {
+	Name        string
+	Inputs      string
+	Outputs     string
+	Timeline    bool
+	Commands    bool
+	Description string
+}
+
+// FormatCapability descreve um formato de container.
+//
+// FormatCapability describes a container format.
+type FormatCapability struct {
+	Name        string
+	Demux       bool
+	Mux         bool
+	Description string
+}
+
+// PixFmtCapability descreve um formato de pixel.
+//
+// PixFmtCapability describes a pixel format.
+type PixFmtCapability struct {
+	Name         string
+	Input        bool
+	Output       bool
+	Hardware     bool
+	Components   int
+	BitsPerPixel int
+}
+
+// HasEncoder informa se o encoder está disponível.
+//
+// HasEncoder reports whether the encoder is available.
+func (c *Capabilities) HasEncoder(name string) bool {
+	_, ok := c.Encoders[name]
+	return ok
+}
+
+// HasDecoder informa se o decoder está disponível.
+//
+// HasDecoder reports whether the decoder is available.
+func (c *Capabilities) HasDecoder(name string) bool {
+	_, ok := c.Decoders[name]
+	return ok
+}
+
+// HasFilter informa se o filtro está disponível.
+//
+// HasFilter reports whether the filter is available.
+func (c *Capabilities) HasFilter(name string) bool {
+	_, ok := c.Filters[name]
+	return ok
+}
+
+// HasMuxer informa se o formato pode ser usado em um output (-f).
+//
+// HasMuxer reports whether the format can be used for an output (-f).
+func (c *Capabilities) HasMuxer(name string) bool {
+	return c.Formats[name].Mux
+}
+
+// HasDemuxer informa se o formato pode ser usado em um input (-f).
+//
+// HasDemuxer reports whether the format can be used for an input (-f).
+func (c *Capabilities) HasDemuxer(name string) bool {
+	return c.Formats[name].Demux
+}
+
+// HasHWAccel informa se o método de aceleração por hardware está disponível.
+//
+// HasHWAccel reports whether the hardware acceleration method is available.
+func (c *Capabilities) HasHWAccel(name string) bool {
+	for _, h := range c.HWAccels {
+		if h == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPixFmt informa se o formato de pixel é conhecido.
+//
+// HasPixFmt reports whether the pixel format is known.
+func (c *Capabilities) HasPixFmt(name string) bool {
+	_, ok := c.PixFmts[name]
+	return ok
+}
+
+// capabilitiesCache guarda o resultado de Discover por binário, ambiente e diretório.
+//
+// capabilitiesCache stores the Discover result per binary, environment and directory.
+var capabilitiesCache sync.Map
+
+// Discover executa o ffmpeg configurado por opts (WithBinary, WithExecutor, WithEnv...)
+// para descobrir suas capacidades. Com o Executor padrão, o resultado é guardado em
+// cache por binário, ambiente (WithEnv) e diretório (WithDir), então apenas a primeira
+// chamada executa o ffmpeg. Com WithExecutor, o ffmpeg é executado em toda chamada,
+// já que o Executor pode apontar para outro ambiente (container, fake etc.); guarde o
+// *Capabilities retornado para reutilizá-lo.
+//
+// Discover runs the ffmpeg configured by opts (WithBinary, WithExecutor, WithEnv...)
+// to discover its capabilities. With the default Executor, the result is cached per
+// binary, environment (WithEnv) and directory (WithDir), so only the first call runs
+// ffmpeg. With WithExecutor, ffmpeg runs on every call, since the Executor may point
+// to another environment (container, fake, etc.); keep the returned *Capabilities
+// to reuse it.
+func Discover(ctx context.Context, opts ...Option) (*Capabilities, error) {
+	b := newBuilder(nil, opts...)
+	key, cacheable := b.capabilitiesKey()
+	if cacheable {
+		if caps, ok := capabilitiesCache.Load(key); ok {
+			return caps.(*Capabilities), nil
+		}
+	}
+
+	sections := []struct {
+		flag  string
+		parse func(*Capabilities, string)
+	}{
+		{"-version", parseVersion},
+		{"-encoders", func(c *Capabilities, out string) { c.Encoders = parseCodecs(out) }},
+		{"-decoders", func(c *Capabilities, out string) { c.Decoders = parseCodecs(out) }},
+		{"-filters", parseFilters},
+		{"-formats", parseFormats},
+		{"-hwaccels", parseHWAccels},
+		{"-pix_fmts", parsePixFmts},
+	}
+
+	caps := &Capabilities{}
+	for _, s := range sections {
+		out, err := b.output(ctx, "-hide_banner", s.flag)
+		if err != nil {
+			return nil, err
+		}
+		s.parse(caps, out)
+	}
+
+	if !cacheable {
+		return caps, nil
+	}
+	actual, _ := capabilitiesCache.LoadOrStore(key, caps)
+	return actual.(*Capabilities), nil
+}
+
+// capabilitiesKey retorna a chave de cache de Discover, ou false quando o builder
+// usa um Executor próprio.
+//
+// capabilitiesKey returns the Discover cache key, or false when the builder uses
+// its own Executor.
+func (b *ffmpegBuilder) capabilitiesKey() (string, bool) {
+	if _, ok := b.executor.(execExecutor); !ok {
+		return "", false
+	}
+	return strings.Join(append([]string{b.binaryPath(), b.dir}, b.env...), "\x00"), true
+}
+
+// WithCapabilities faz writeStage.Validate conferir codecs, filtros, formatos,
+// acelerações por hardware e formatos de pixel contra caps.
+//
+// WithCapabilities makes writeStage.Validate check codecs, filters, formats,
+// hardware accelerations and pixel formats against caps.
+func WithCapabilities(caps *Capabilities) Option {
+	return func(b *ffmpegBuilder) { b.caps = caps }
+}
+
+// output executa o ffmpeg com args e retorna o stdout.
+//
+// output runs ffmpeg with args and returns its stdout.
+func (b *ffmpegBuilder) output(ctx context.Context, args ...string) (string, error) {
+	spec := ExecSpec{Path: b.binaryPath(), Args: args, Env: b.env, Dir: b.dir}
+	executor := b.executor
+	if executor == nil {
+		executor = execExecutor{}
+	}
+	proc, err := executor.Start(ctx, spec)
+	if err != nil {
+		return "", err
+	}
+
+	tail := newStderrTail(b.stderrLines)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := newLogScanner(proc.Stderr())
+		for scanner.Scan() {
+			tail.Add(scanner.Text())
+		}
+		_, _ = io.Copy(io.Discard, proc.Stderr())
+	}()
+
+	var stdout bytes.Buffer
+	_, _ = io.Copy(&stdout, proc.Stdout())
+	<-done
+
+	if err := proc.Wait(); err != nil {
+		return "", newFFmpegError(spec.argv(), tail.Lines(), err)
+	}
+	return stdout.String(), nil
+}
+
+func parseVersion(c *Capabilities, out string) {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 3 && fields[1] == "version" && c.Version == "":
+			c.Version = fields[2]
+		case len(fields) > 0 && fields[0] == "configuration:":
+			c.Configuration = fields[1:]
+		}
+	}
+}
+
+// tableRows retorna as linhas que vêm depois do separador ("---...") de uma listagem do ffmpeg.
+//
+// tableRows returns the lines after the separator ("---...") of an ffmpeg listing.
+func tableRows(out string) []string {
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line != "" && strings.Trim(line, "-") == "" {
+			return lines[i+1:]
+		}
+	}
+	return nil
+}
+
+func parseCodecs(out string) map[string]CodecCapability {
+	codecs := map[string]CodecCapability{}
+	for _, line := range tableRows(out) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		flags := fields[0]
+		codec := CodecCapability{
+			Name:         fields[1],
+			Experimental: len(flags) > 3 && flags[3] == 'X',
+			Description:  strings.Join(fields[2:], " "),
+		}
+		switch flags[0] {
+		case 'V':
+			codec.Type = Video
+		case 'A':
+			codec.Type = Audio
+		case 'S':
+			codec.Type = Subtitle
+		case 'D':
+			codec.Type = Data
+		case 'T':
+			codec.Type = Attachment
+		}
+		codecs[codec.Name] = codec
+	}
+	return codecs
+}
+
+func parseFilters(c *Capabilities, out string) {
+	c.Filters = map[string]FilterCapability{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		in, outs, ok := strings.Cut(fields[2], "->")
+		if !ok {
+			continue
+		}
+		flags := fields[0]
+		c.Filters[fields[1]] = FilterCapability{
+			Name:        fields[1],
+			Inputs:      in,
+			Outputs:     outs,
+			Timeline:    strings.HasPrefix(flags, "T"),
+			Commands:    len(flags) > 2 && flags[2] == 'C',
+			Description: strings.Join(fields[3:], " "),
+		}
+	}
+}
+
+// parseFormats lê a listagem de -formats. As flags ocupam colunas fixas, cuja largura
+// vem da legenda ("D. = Demuxing supported", ou "D.. =" nas versões com devices).
+//
+// parseFormats reads the -formats listing. The flags use fixed columns, whose width
+// comes from the legend ("D. = Demuxing supported", or "D.. =" in versions with devices).
+func parseFormats(c *Capabilities, out string) {
+	c.Formats = map[string]FormatCapability{}
+
+	width := 2
+	for _, line := range strings.Split(out, "\n") {
+		if legend, _, ok := strings.Cut(strings.TrimSpace(line), " = Demuxing"); ok {
+			width = len(legend)
+			break
+		}
+	}
+
+	for _, line := range tableRows(out) {
+		if len(line) < width+2 {
+			continue
+		}
+		flags := line[1 : 1+width]
+		fields := strings.Fields(line[1+width:])
+		if len(fields) == 0 {
+			continue
+		}
+		for _, name := range strings.Split(fields[0], ",") {
+			f := c.Formats[name]
+			f.Name = name
+			f.Demux = f.Demux || flags[0] == 'D'
+			f.Mux = f.Mux || flags[1] == 'E'
+			f.Description = strings.Join(fields[1:], " ")
+			c.Formats[name] = f
+		}
+	}
+}
+
+func parseHWAccels(c *Capabilities, out string) {
+	lines := strings.Split(out, "\n")
+	for _, line := range lines[1:] {
+		if line = strings.TrimSpace(line); line != "" {
+			c.HWAccels = append(c.HWAccels, line)
+		}
+	}
+}
+
+func parsePixFmts(c *Capabilities, out string) {
+	c.PixFmts = map[string]PixFmtCapability{}
+	for _, line := range tableRows(out) {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || len(fields[0]) < 3 {
+			continue
+		}
+		flags := fields[0]
+		components, _ := strconv.Atoi(fields[2])
+		bpp, _ := strconv.Atoi(fields[3])
+		c.PixFmts[fields[1]] = PixFmtCapability{
+			Name:         fields[1],
+			Input:        flags[0] == 'I',
+			Output:       flags[1] == 'O',
+			Hardware:     flags[2] == 'H',
+			Components:   components,
+			BitsPerPixel: bpp,
+		}
+	}
+}
+
+// checkCapabilities confere os nomes usados pelo builder contra caps.
+//
+// checkCapabilities checks the names used by the builder against caps.
+func (b *ffmpegBuilder) checkCapabilities(caps *Capabilities) error {
+	var errs []error
+
+	for i, in := range b.inputs {
+		eachOption(in.args, func(flag, value string) {
+			switch {
+			case flag == "-f" && !caps.HasDemuxer(value):
+				errs = append(errs, fmt.Errorf("capabilities: input %d: unknown demuxer %q", i, value))
+			case flag == "-hwaccel" && value != "auto" && value != "none" && !caps.HasHWAccel(value):
+				errs = append(errs, fmt.Errorf("capabilities: input %d: unsupported hwaccel %q", i, value))
+			case (flag == "-c" || strings.HasPrefix(flag, "-c:")) && !caps.HasDecoder(value):
+				errs = append(errs, fmt.Errorf("capabilities: input %d: unknown decoder %q", i, value))
+			}
+		})
+	}
+
+	checkFilters := func(filters []AtomicFilter) {
+		for _, f := range filters {
+			if !caps.HasFilter(f.Name) {
+				errs = append(errs, fmt.Errorf("capabilities: unknown filter %q", f.Name))
+			}
+		}
+	}
+	for _, c := range b.graph().Chains {
+		checkFilters(c.Filter)
+	}
+
+	for i, o := range b.outputs {
+		for _, c := range o.filters {
+			checkFilters(c.filters)
+		}
+		eachOption(o.args, func(flag, value string) {
+			_, isCodec := codecStream(flag)
+			switch {
+			case isCodec && value != "copy" && !caps.HasEncoder(value):
+				errs = append(errs, fmt.Errorf("capabilities: output %d: unknown encoder %q", i, value))
+			case flag == "-f" && !caps.HasMuxer(value):
+				errs = append(errs, fmt.Errorf("capabilities: output %d: unknown muxer %q", i, value))
+			case flag == "-pix_fmt" && !caps.HasPixFmt(value):
+				errs = append(errs, fmt.Errorf("capabilities: output %d: unknown pixel format %q", i, value))
+			}
+		})
+	}
+
+	return errors.Join(errs...)
+}
+
+// eachOption chama fn para cada par flag/valor de args.
+//
+// eachOption calls fn for each flag/value pair of args.
+func eachOption(args []string, fn func(flag, value string)) {
+	for i := 0; i+1 < len(args); i++ {
+		if isFlag(args[i]) && !booleanFlags[flagName(args[i])] {
+			fn(args[i], args[i+1])
+			i++
+		}
+	}
+}
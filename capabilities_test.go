@@ -0,0 +1,207 @@
+package fflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	versionOutput = `ffmpeg version 6.1.1-3ubuntu5 Copyright (c) 2000-2023 the FFmpeg developers
+built with gcc 13 (Ubuntu 13.2.0-23ubuntu3)
+configuration: --prefix=/usr --enable-gpl --enable-libx264
+libavutil      58. 29.100 / 58. 29.100
+`
+	encodersOutput = `Encoders:
+ V..... = Video
+ A..... = Audio
+ S..... = Subtitle
+ .F.... = Frame-level multithreading
+ ..S... = Slice-level multithreading
+ ...X.. = Codec is experimental
+ ....B. = Supports draw_horiz_band
+ .....D = Supports direct rendering method 1
+ ------
+ V....D libx264              libx264 H.264 / AVC / MPEG-4 AVC / MPEG-4 part 10 (codec h264)
+ V....D h264_nvenc           NVIDIA NVENC H.264 encoder (codec h264)
+ A....D aac                  AAC (Advanced Audio Coding)
+ A..X.D opus                 Opus
+ S..... mov_text             3GPP Timed Text subtitle
+`
+	decodersOutput = `Decoders:
+ V..... = Video
+ ------
+ VFS..D h264                 H.264 / AVC / MPEG-4 AVC / MPEG-4 part 10
+ A....D aac                  AAC (Advanced Audio Coding)
+`
+	filtersOutput = `Filters:
+  T.. = Timeline support
+  .S. = Slice threading
+  ..C = Command support
+  A = Audio input/output
+  V = Video input/output
+  N = Dynamic number and/or type of input/output
+  | = Source or sink filter
+ ..C scale             V->V       Scale the input video size and/or convert the image format.
+ T.C overlay           VV->V      Overlay a video source on top of the input.
+ ... amix              N->A       Audio mixing.
+ ... testsrc           |->V       Generate test pattern.
+`
+	formatsOutput = `File formats:
+ D.. = Demuxing supported
+ .E. = Muxing supported
+ ..d = Is a device
+ ---
+ DE  matroska,webm    Matroska / WebM
+  E  mp4              MP4 (MPEG-4 Part 14)
+ D   mov,mp4,m4a      QuickTime / MOV
+ D d v4l2             Video4Linux2 device grab
+`
+	hwaccelsOutput = `Hardware acceleration methods:
+vdpau
+cuda
+vaapi
+
+`
+	pixFmtsOutput = `Pixel formats:
+I.... = Supported Input  format for conversion
+.O... = Supported Output format for conversion
+..H.. = Hardware accelerated format
+...P. = Paletted format
+....B = Bitstream format
+FLAGS NAME            NB_COMPONENTS BITS_PER_PIXEL BIT_DEPTHS
+-----
+IO... yuv420p                3             12      8-8-8
+..H.. cuda                   0              0      0
+`
+)
+
+// capsExecutor responde cada listagem do ffmpeg com a saída correspondente.
+type capsExecutor struct {
+	outputs map[string]string
+	calls   int
+}
+
+func (e *capsExecutor) Start(_ context.Context, spec ExecSpec) (Process, error) {
+	e.calls++
+	flag := spec.Args[len(spec.Args)-1]
+	out, ok := e.outputs[flag]
+	if !ok {
+		return &fakeProcess{stdout: strings.NewReader(""), stderr: strings.NewReader("Unrecognized option\n"), err: &fakeExitError{code: 1}}, nil
+	}
+	return &fakeProcess{stdout: strings.NewReader(out), stderr: strings.NewReader("")}, nil
+}
+
+func newCapsExecutor() *capsExecutor {
+	return &capsExecutor{outputs: map[string]string{
+		"-version":  versionOutput,
+		"-encoders": encodersOutput,
+		"-decoders": decodersOutput,
+		"-filters":  filtersOutput,
+		"-formats":  formatsOutput,
+		"-hwaccels": hwaccelsOutput,
+		"-pix_fmts": pixFmtsOutput,
+	}}
+}
+
+func TestCapabilities(t *testing.T) {
+	exec := newCapsExecutor()
+	caps, err := Discover(context.Background(), WithExecutor(exec), WithBinary("/opt/ffmpeg-test/ffmpeg"))
+	require.NoError(t, err)
+
+	t.Run("Interpreta as listagens", func(t *testing.T) {
+		assert.Equal(t, "6.1.1-3ubuntu5", caps.Version)
+		assert.Equal(t, []string{"--prefix=/usr", "--enable-gpl", "--enable-libx264"}, caps.Configuration)
+
+		assert.Equal(t, CodecCapability{Name: "h264_nvenc", Type: Video, Description: "NVIDIA NVENC H.264 encoder (codec h264)"}, caps.Encoders["h264_nvenc"])
+		assert.True(t, caps.Encoders["opus"].Experimental)
+		assert.Equal(t, Subtitle, caps.Encoders["mov_text"].Type)
+		assert.True(t, caps.HasDecoder("h264"))
+		assert.False(t, caps.HasEncoder("libx265"))
+
+		assert.Equal(t, FilterCapability{Name: "overlay", Inputs: "VV", Outputs: "V", Timeline: true, Commands: true,
+			Description: "Overlay a video source on top of the input."}, caps.Filters["overlay"])
+		assert.True(t, caps.HasFilter("testsrc"))
+		assert.False(t, caps.HasFilter("libplacebo"))
+
+		assert.True(t, caps.HasMuxer("webm"))
+		assert.True(t, caps.HasMuxer("mp4"))
+		assert.True(t, caps.HasDemuxer("mp4"))
+		assert.False(t, caps.HasMuxer("mov"))
+		assert.True(t, caps.HasDemuxer("v4l2"))
+
+		assert.Equal(t, []string{"vdpau", "cuda", "vaapi"}, caps.HWAccels)
+		assert.Equal(t, PixFmtCapability{Name: "yuv420p", Input: true, Output: true, Components: 3, BitsPerPixel: 12}, caps.PixFmts["yuv420p"])
+		assert.True(t, caps.PixFmts["cuda"].Hardware)
+	})
+
+	t.Run("Executor próprio não usa o cache", func(t *testing.T) {
+		calls := exec.calls
+		again, err := Discover(context.Background(), WithExecutor(exec), WithBinary("/opt/ffmpeg-test/ffmpeg"))
+		require.NoError(t, err)
+		assert.NotSame(t, caps, again)
+		assert.Equal(t, caps, again)
+		assert.Greater(t, exec.calls, calls)
+
+		_, cacheable := newBuilder(nil, WithExecutor(exec)).capabilitiesKey()
+		assert.False(t, cacheable)
+	})
+
+	t.Run("Chave do cache inclui binário, ambiente e diretório", func(t *testing.T) {
+		key := func(opts ...Option) string {
+			k, ok := newBuilder(nil, opts...).capabilitiesKey()
+			require.True(t, ok)
+			return k
+		}
+
+		base := key()
+		assert.Equal(t, base, key(WithBinary(DefaultBinary)))
+		assert.NotEqual(t, base, key(WithBinary("/opt/ffmpeg/bin/ffmpeg")))
+		assert.NotEqual(t, base, key(WithEnv("PATH=/opt/ffmpeg/bin")))
+		assert.NotEqual(t, base, key(WithDir("/tmp")))
+	})
+
+	t.Run("Retorna FFmpegError quando uma listagem falha", func(t *testing.T) {
+		broken := newCapsExecutor()
+		delete(broken.outputs, "-hwaccels")
+		_, err := Discover(context.Background(), WithExecutor(broken), WithBinary("/opt/ffmpeg-broken/ffmpeg"))
+
+		var ffErr *FFmpegError
+		require.True(t, errors.As(err, &ffErr))
+		assert.Equal(t, 1, ffErr.ExitCode)
+	})
+
+	t.Run("Validate confere os nomes com WithCapabilities", func(t *testing.T) {
+		w := New(WithCapabilities(caps)).
+			Input("in.mp4", InputHWAccel("qsv")).
+			Filter().
+			Simple(FilterVideo).
+			Add(AtomicFilter{Name: "libplacebo"}).
+			Add(AtomicFilter{Name: "scale", Params: []string{"1280", "-2"}}).
+			Done().
+			VideoCodec("libx265").
+			AudioCodec("copy").
+			Raw("-f", "mov").
+			Output("out.mov")
+
+		err := w.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `capabilities: input 0: unsupported hwaccel "qsv"`)
+		assert.Contains(t, err.Error(), `capabilities: unknown filter "libplacebo"`)
+		assert.Contains(t, err.Error(), `capabilities: output 0: unknown encoder "libx265"`)
+		assert.Contains(t, err.Error(), `capabilities: output 0: unknown muxer "mov"`)
+		assert.NotContains(t, err.Error(), "scale")
+		assert.NotContains(t, err.Error(), "copy")
+
+		ok := New(WithCapabilities(caps)).
+			Input("in.mp4", InputHWAccel("cuda")).
+			Output("out.mp4").
+			VideoCodec("h264_nvenc").
+			Raw("-pix_fmt", "yuv420p")
+		assert.NoError(t, ok.Validate())
+	})
+}
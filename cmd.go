@@ -1,12 +1,13 @@
 package fflow
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,28 +18,48 @@ type commandStage interface {
 	// String returns the final command (debug/log).
 	String() string
 
-	// Cmd retorna *exec.Cmd pronto para executar.
+	// Cmd retorna *exec.Cmd pronto para executar. Com WithFilterScript, o arquivo
+	// do filtergraph é removido quando ctx terminar; se ctx nunca termina
+	// (ex.: context.Background), o filtergraph é passado inline para não deixar
+	// arquivos para trás. Use CmdWithCleanup para controlar a remoção.
 	//
-	// Cmd returns *exec.Cmd ready to execute.
+	// Cmd returns *exec.Cmd ready to execute. With WithFilterScript, the filtergraph
+	// file is removed when ctx is done; if ctx can never be done
+	// (e.g. context.Background), the filtergraph is passed inline so that no files
+	// are left behind. Use CmdWithCleanup to control the removal.
 	Cmd(ctx context.Context) *exec.Cmd
 
-	// Run Executa o comando
+	// CmdWithCleanup funciona como Cmd, mas sempre respeita WithFilterScript e retorna
+	// uma função que remove o arquivo do filtergraph. Chame-a depois que o comando terminar.
 	//
-	// Run execute the command
+	// CmdWithCleanup works like Cmd, but always honours WithFilterScript and returns
+	// a function that removes the filtergraph file. Call it after the command finishes.
+	CmdWithCleanup(ctx context.Context) (*exec.Cmd, func())
+
+	// Run valida o builder (ver writeStage.Validate) e executa o comando.
+	//
+	// Run validates the builder (see writeStage.Validate) and executes the command.
 	Run(ctx context.Context) error
 
-	// RunWithProgress executa o comando e emite eventos de progresso.
+	// RunWithProgress executa RunWithProgressFunc em uma goroutine. O canal de progresso
+	// guarda apenas o evento mais recente, então um consumidor lento ou que parou de ler
+	// não bloqueia o ffmpeg. O canal de erro recebe exatamente um valor (nil em caso de
+	// sucesso) depois que o canal de progresso é fechado.
 	//
-	// RunWithProgress executes the command and emits progress events.
+	// RunWithProgress runs RunWithProgressFunc in a goroutine. The progress channel
+	// keeps only the latest event, so a slow consumer, or one that stopped reading,
+	// does not block ffmpeg. The error channel receives exactly one value (nil on
+	// success) after the progress channel is closed.
 	RunWithProgress(ctx context.Context) (<-chan Progress, <-chan error)
-}
 
-type Progress struct {
-	Frame   int
-	FPS     float64
-	Bitrate string
-	OutTime time.Duration
-	Speed   string
+	// RunWithProgressFunc valida o builder, executa o comando e chama fn, na goroutine
+	// atual, para cada evento de progresso. Falhas do ffmpeg são detectadas pelo status
+	// de saída e retornadas como *FFmpegError.
+	//
+	// RunWithProgressFunc validates the builder, executes the command and calls fn, on
+	// the current goroutine, for each progress event. ffmpeg failures are detected from
+	// the exit status and returned as *FFmpegError.
+	RunWithProgressFunc(ctx context.Context, fn func(Progress)) error
 }
 
 type commandCtx struct{ b *ffmpegBuilder }
@@ -48,74 +69,232 @@ func (c *commandCtx) String() string {
 }
 
 func (c *commandCtx) Cmd(ctx context.Context) *exec.Cmd {
-	return exec.CommandContext(ctx, "ffmpeg", c.tmpWritter().Args()...)
+	if ctx.Done() == nil {
+		return c.newCmd(ctx, c.tmpWritter().Args())
+	}
+
+	cmd, cleanup := c.CmdWithCleanup(ctx)
+	context.AfterFunc(ctx, cleanup)
+	return cmd
 }
 
-func (c *commandCtx) Run(ctx context.Context) error {
-	cmd := c.Cmd(ctx)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+func (c *commandCtx) CmdWithCleanup(ctx context.Context) (*exec.Cmd, func()) {
+	args, cleanup, err := c.b.runArgs()
+	if err != nil {
+		cmd := c.newCmd(ctx, c.tmpWritter().Args())
+		cmd.Err = err
+		return cmd, func() {}
+	}
+	return c.newCmd(ctx, args), cleanup
 }
 
-func (c *commandCtx) RunWithProgress(ctx context.Context) (<-chan Progress, <-chan error) {
-	args := c.tmpWritter().Args()
-	args = append(args, "-progress", "pipe:2", "-nostats")
+func (c *commandCtx) newCmd(ctx context.Context, args []string) *exec.Cmd {
+	cmd := newExecCmd(ctx, c.spec(args))
+	if c.b.stdout != nil {
+		cmd.Stdout = c.b.stdout
+	}
+	if len(c.b.extraPipes) > 0 {
+		cmd.Err = errors.New("fflow: Cmd does not support extra pipes (pipe:3 onwards); use Run or RunWithProgress")
+	}
+	return cmd
+}
+
+func (c *commandCtx) Run(ctx context.Context) error {
+	if err := c.b.validate(); err != nil {
+		return err
+	}
 
-	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
-	stderr, err := cmd.StderrPipe()
+	args, cleanup, err := c.b.runArgs()
 	if err != nil {
-		return nil, errChan(err)
+		return err
 	}
+	defer cleanup()
 
-	err = cmd.Start()
+	p, err := c.b.openPipes()
 	if err != nil {
-		return nil, errChan(err)
+		return err
+	}
+
+	spec := c.spec(args)
+	spec.ExtraFiles = p.child
+	proc, err := c.executor().Start(ctx, spec)
+	if err != nil {
+		p.close()
+		return err
+	}
+	p.start()
+
+	stdout := io.Writer(os.Stdout)
+	if c.b.stdout != nil {
+		stdout = c.b.stdout
+	}
+
+	tail := newStderrTail(c.b.stderrLines)
+	done := make(chan error, 1)
+	go func() {
+		done <- copyOutput(stdout, proc.Stdout())
+	}()
+
+	stderr := proc.Stderr()
+	if c.b.logFunc == nil && c.b.logger == nil {
+		stderr = io.TeeReader(stderr, os.Stderr)
 	}
+	c.readLog(ctx, stderr, tail)
+	stdoutErr := <-done
 
-	pch := make(chan Progress)
+	if err := proc.Wait(); err != nil {
+		_ = p.wait()
+		return newFFmpegError(spec.argv(), tail.Lines(), err)
+	}
+	return errors.Join(stdoutErr, p.wait())
+}
+
+func (c *commandCtx) RunWithProgress(ctx context.Context) (<-chan Progress, <-chan error) {
+	pch := make(chan Progress, 1)
 	ech := make(chan error, 1)
 
 	go func() {
-		defer close(pch)
 		defer close(ech)
+		err := c.RunWithProgressFunc(ctx, func(p Progress) { sendLatest(pch, p) })
+		close(pch)
+		ech <- err
+	}()
 
-		if err := c.monitorProgress(stderr, pch); err != nil {
-			ech <- err
-			_ = cmd.Process.Kill()
-			return
-		}
+	return pch, ech
+}
+
+func (c *commandCtx) RunWithProgressFunc(ctx context.Context, fn func(Progress)) error {
+	if err := c.b.validate(); err != nil {
+		return err
+	}
+
+	args, cleanup, err := c.b.runArgs()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 
-		if err := cmd.Wait(); err != nil {
-			ech <- fmt.Errorf("ffmpeg failed: %w", err)
+	p, err := c.b.openPipes()
+	if err != nil {
+		return err
+	}
+
+	spec := c.spec(args)
+	spec.ExtraFiles = p.child
+	pr, pw, err := progressPipe(&spec, supportsExtraFiles(c.executor()))
+	if err != nil {
+		p.close()
+		return err
+	}
+
+	proc, err := c.executor().Start(ctx, spec)
+	if pw != nil {
+		_ = pw.Close()
+	}
+	if err != nil {
+		p.close()
+		if pr != nil {
+			_ = pr.Close()
 		}
+		return err
+	}
+	p.start()
 
-		ech <- nil
+	stdout := io.Discard
+	if c.b.stdout != nil {
+		stdout = c.b.stdout
+	}
+	drained := make(chan error, 1)
+	go func() {
+		drained <- copyOutput(stdout, proc.Stdout())
 	}()
 
-	return pch, ech
+	tail := newStderrTail(c.b.stderrLines)
+	if pr == nil {
+		c.monitorProgress(proc.Stderr(), fn, func(line string) { c.logLine(ctx, line, tail) })
+		_, _ = io.Copy(io.Discard, proc.Stderr())
+	} else {
+		logged := make(chan struct{})
+		go func() {
+			defer close(logged)
+			c.readLog(ctx, proc.Stderr(), tail)
+		}()
+		c.monitorProgress(pr, fn, func(string) {})
+		_, _ = io.Copy(io.Discard, pr)
+		_ = pr.Close()
+		<-logged
+	}
+	stdoutErr := <-drained
+
+	if err := proc.Wait(); err != nil {
+		_ = p.wait()
+		return newFFmpegError(spec.argv(), tail.Lines(), err)
+	}
+	return errors.Join(stdoutErr, p.wait())
+}
+
+// sendLatest envia p sem bloquear, descartando o evento anterior que ainda não foi lido.
+//
+// sendLatest sends p without blocking, dropping the previous event that was not read yet.
+func sendLatest(ch chan Progress, p Progress) {
+	for {
+		select {
+		case ch <- p:
+			return
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	}
+}
+
+// copyOutput copia o stdout do ffmpeg para w. Se w falhar, o restante é descartado
+// para que o ffmpeg não fique bloqueado escrevendo.
+//
+// copyOutput copies ffmpeg's stdout to w. If w fails, the rest is discarded
+// so that ffmpeg does not block while writing.
+func copyOutput(w io.Writer, r io.Reader) error {
+	_, err := io.Copy(w, r)
+	if err != nil {
+		_, _ = io.Copy(io.Discard, r)
+	}
+	return err
 }
 
-func (c commandCtx) monitorProgress(stderr io.ReadCloser, pch chan Progress) error {
-	scanner := bufio.NewScanner(stderr)
+// monitorProgress lê os blocos de -progress e chama fn a cada "progress=". As demais
+// linhas vão para logLine; a falha é detectada pelo status de saída do ffmpeg.
+//
+// monitorProgress reads the -progress blocks and calls fn on each "progress=". The other
+// lines go to logLine; failure is detected from ffmpeg's exit status.
+func (c commandCtx) monitorProgress(r io.Reader, fn func(Progress), logLine func(string)) {
+	scanner := newLogScanner(r)
 
+	total := c.b.expectedDuration()
 	prog := Progress{}
 	for scanner.Scan() {
 		line := scanner.Text()
 
-		if strings.HasPrefix(strings.ToLower(line), "error") ||
-			strings.Contains(strings.ToLower(line), "invalid") {
-			return fmt.Errorf("ffmpeg error: %s", line)
-		}
-
+		// INFO: linhas de log também podem conter "=", mas as chaves do -progress não têm espaços.
 		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
+		if len(parts) != 2 || strings.ContainsAny(parts[0], " [") {
+			logLine(line)
 			continue
 		}
 
 		key := parts[0]
 		value := parts[1]
 
+		if ref, ok := quantizerKey(key); ok {
+			if prog.Quantizers == nil {
+				prog.Quantizers = map[string]float64{}
+			}
+			q, _ := strconv.ParseFloat(value, 64)
+			prog.Quantizers[ref] = q
+			continue
+		}
+
 		switch key {
 		case "frame":
 			fmt.Sscanf(value, "%d", &prog.Frame)
@@ -126,36 +305,54 @@ func (c commandCtx) monitorProgress(stderr io.ReadCloser, pch chan Progress) err
 		case "bitrate":
 			prog.Bitrate = value
 
+		case "total_size":
+			fmt.Sscanf(value, "%d", &prog.TotalSize)
+
+		// INFO: apesar do nome, out_time_ms também é emitido em microssegundos.
+		case "out_time_us", "out_time_ms":
+			var us int64
+			if _, err := fmt.Sscanf(value, "%d", &us); err == nil {
+				prog.OutTime = time.Duration(us) * time.Microsecond
+			}
+
 		case "out_time":
-			var h, m int
-			var sec float64
-			fmt.Sscanf(value, "%d:%d:%f", &h, &m, &sec)
-			d := time.Duration(h)*time.Hour +
-				time.Duration(m)*time.Minute +
-				time.Duration(sec*float64(time.Second))
+			prog.OutTime, _ = parseDuration(value)
+
+		case "dup_frames":
+			fmt.Sscanf(value, "%d", &prog.DupFrames)
 
-			prog.OutTime = d
+		case "drop_frames":
+			fmt.Sscanf(value, "%d", &prog.DropFrames)
 
 		case "speed":
 			fmt.Sscanf(value, "%s", &prog.Speed)
+			prog.SpeedFactor = parseSpeed(prog.Speed)
 
 		case "progress":
-			pch <- prog
-
-			if value == "end" {
-				return nil
-			}
+			prog.estimate(total)
+			fn(prog)
+			prog.Quantizers = nil
 		}
 	}
+}
 
-	return scanner.Err()
+func (c *commandCtx) spec(args []string) ExecSpec {
+	return ExecSpec{
+		Path:        c.b.binaryPath(),
+		Args:        args,
+		Env:         c.b.env,
+		Dir:         c.b.dir,
+		Stdin:       c.b.stdin,
+		CancelMode:  c.b.cancelMode,
+		GracePeriod: c.b.grace,
+	}
 }
 
-func errChan(err error) <-chan error {
-	ch := make(chan error, 1)
-	ch <- err
-	close(ch)
-	return ch
+func (c *commandCtx) executor() Executor {
+	if c.b.executor == nil {
+		return execExecutor{}
+	}
+	return c.b.executor
 }
 
 func (c *commandCtx) tmpWritter() *writeCtx {
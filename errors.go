@@ -0,0 +1,160 @@
+package fflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorKind classifica a causa provável de uma falha do ffmpeg.
+//
+// ErrorKind classifies the probable cause of an ffmpeg failure.
+type ErrorKind int
+
+const (
+	KindUnknown ErrorKind = iota
+	KindInputNotFound
+	KindUnknownEncoder
+	KindInvalidArgument
+	KindPermissionDenied
+	KindDiskFull
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindInputNotFound:
+		return "input not found"
+	case KindUnknownEncoder:
+		return "unknown encoder"
+	case KindInvalidArgument:
+		return "invalid argument"
+	case KindPermissionDenied:
+		return "permission denied"
+	case KindDiskFull:
+		return "disk full"
+	}
+	return "unknown"
+}
+
+// defaultStderrLines é a quantidade de linhas do stderr mantidas em FFmpegError.
+//
+// defaultStderrLines is the number of stderr lines kept in FFmpegError.
+const defaultStderrLines = 20
+
+// FFmpegError descreve uma execução do ffmpeg que terminou com falha.
+// Pode ser obtido com errors.As.
+//
+// FFmpegError describes an ffmpeg run that failed.
+// It can be retrieved with errors.As.
+type FFmpegError struct {
+	// ExitCode é o código de saída do processo, ou -1 quando não está disponível.
+	//
+	// ExitCode is the process exit code, or -1 when it is not available.
+	ExitCode int
+
+	// Args é o argv completo, incluindo o binário.
+	//
+	// Args is the full argv, including the binary.
+	Args []string
+
+	// Stderr contém as últimas linhas escritas pelo ffmpeg no stderr.
+	//
+	// Stderr holds the last lines written by ffmpeg to stderr.
+	Stderr []string
+
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *FFmpegError) Error() string {
+	msg := fmt.Sprintf("ffmpeg failed (exit code %d, %s)", e.ExitCode, e.Kind)
+	if len(e.Stderr) > 0 {
+		msg += ": " + e.Stderr[len(e.Stderr)-1]
+	}
+	return msg
+}
+
+func (e *FFmpegError) Unwrap() error {
+	return e.Err
+}
+
+func newFFmpegError(argv []string, stderr []string, err error) *FFmpegError {
+	code := -1
+	var exit interface{ ExitCode() int }
+	if errors.As(err, &exit) {
+		code = exit.ExitCode()
+	}
+
+	return &FFmpegError{
+		ExitCode: code,
+		Args:     argv,
+		Stderr:   stderr,
+		Kind:     classifyStderr(stderr),
+		Err:      err,
+	}
+}
+
+var errorKindPatterns = []struct {
+	kind     ErrorKind
+	patterns []string
+}{
+	{KindInputNotFound, []string{"No such file or directory"}},
+	{KindUnknownEncoder, []string{"Unknown encoder", "Encoder not found"}},
+	{KindPermissionDenied, []string{"Permission denied"}},
+	{KindDiskFull, []string{"No space left on device"}},
+	{KindInvalidArgument, []string{"Invalid argument", "Unrecognized option", "Option not found"}},
+}
+
+// classifyStderr procura, da última linha para a primeira, uma mensagem conhecida do ffmpeg.
+//
+// classifyStderr looks, from the last line to the first, for a known ffmpeg message.
+func classifyStderr(lines []string) ErrorKind {
+	for i := len(lines) - 1; i >= 0; i-- {
+		for _, k := range errorKindPatterns {
+			for _, p := range k.patterns {
+				if strings.Contains(lines[i], p) {
+					return k.kind
+				}
+			}
+		}
+	}
+	return KindUnknown
+}
+
+// stderrTail é um buffer circular com as últimas linhas do stderr.
+//
+// stderrTail is a ring buffer holding the last stderr lines.
+type stderrTail struct {
+	lines []string
+	next  int
+	full  bool
+}
+
+func newStderrTail(size int) *stderrTail {
+	if size <= 0 {
+		size = defaultStderrLines
+	}
+	return &stderrTail{lines: make([]string, size)}
+}
+
+func (t *stderrTail) Add(line string) {
+	t.lines[t.next] = line
+	t.next = (t.next + 1) % len(t.lines)
+	if t.next == 0 {
+		t.full = true
+	}
+}
+
+func (t *stderrTail) Lines() []string {
+	if !t.full {
+		return append([]string(nil), t.lines[:t.next]...)
+	}
+	return append(append([]string(nil), t.lines[t.next:]...), t.lines[:t.next]...)
+}
+
+// WithStderrLines define quantas linhas do stderr são mantidas em FFmpegError.
+//
+// WithStderrLines sets how many stderr lines are kept in FFmpegError.
+func WithStderrLines(n int) Option {
+	return func(b *ffmpegBuilder) { b.stderrLines = n }
+}
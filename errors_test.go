@@ -0,0 +1,90 @@
+package fflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExitError struct{ code int }
+
+func (e fakeExitError) Error() string { return "exit status" }
+func (e fakeExitError) ExitCode() int { return e.code }
+
+func TestFFmpegError(t *testing.T) {
+	t.Run("Classificação do stderr", func(t *testing.T) {
+		tests := []struct {
+			name string
+			line string
+			kind ErrorKind
+		}{
+			{"Input inexistente", "missing.mp4: No such file or directory", KindInputNotFound},
+			{"Encoder desconhecido", "Unknown encoder 'libfoo'", KindUnknownEncoder},
+			{"Argumento inválido", "Error opening output out.mp4: Invalid argument", KindInvalidArgument},
+			{"Permissão negada", "out.mp4: Permission denied", KindPermissionDenied},
+			{"Disco cheio", "av_interleaved_write_frame(): No space left on device", KindDiskFull},
+			{"Mensagem desconhecida", "Conversion failed!", KindUnknown},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.kind, classifyStderr([]string{tt.line}))
+			})
+		}
+	})
+
+	t.Run("Buffer circular mantém as últimas linhas", func(t *testing.T) {
+		tail := newStderrTail(3)
+		tail.Add("a")
+		tail.Add("b")
+		assert.Equal(t, []string{"a", "b"}, tail.Lines())
+
+		tail.Add("c")
+		tail.Add("d")
+		tail.Add("e")
+		assert.Equal(t, []string{"c", "d", "e"}, tail.Lines())
+	})
+
+	t.Run("Run retorna *FFmpegError", func(t *testing.T) {
+		fake := &fakeExecutor{
+			stderr: "line 1\nline 2\nmissing.mp4: No such file or directory\n",
+			err:    fakeExitError{code: 254},
+		}
+		err := New(WithExecutor(fake), WithStderrLines(2)).
+			Input("missing.mp4").
+			Output("out.mp4").
+			Command().
+			Run(context.Background())
+
+		var ffErr *FFmpegError
+		require.True(t, errors.As(err, &ffErr))
+		assert.Equal(t, 254, ffErr.ExitCode)
+		assert.Equal(t, KindInputNotFound, ffErr.Kind)
+		assert.Equal(t, []string{"line 2", "missing.mp4: No such file or directory"}, ffErr.Stderr)
+		assert.Equal(t, []string{"ffmpeg", "-loglevel", "error", "-y", "-i", "missing.mp4", "out.mp4"}, ffErr.Args)
+		assert.ErrorIs(t, err, fake.err)
+		assert.EqualError(t, err, "ffmpeg failed (exit code 254, input not found): missing.mp4: No such file or directory")
+	})
+
+	t.Run("RunWithProgress retorna *FFmpegError", func(t *testing.T) {
+		fake := &fakeExecutor{
+			stderr:   "[libx264 @ 0x1] Unknown encoder 'libx265'\n",
+			progress: "frame=1\nprogress=continue\n",
+			err:      fakeExitError{code: 1},
+		}
+		pch, ech := New(WithExecutor(fake)).Input("in.mp4").Output("out.mp4").Command().RunWithProgress(context.Background())
+		for range pch {
+		}
+
+		var ffErr *FFmpegError
+		require.True(t, errors.As(<-ech, &ffErr))
+		assert.Equal(t, 1, ffErr.ExitCode)
+		assert.Equal(t, KindUnknownEncoder, ffErr.Kind)
+
+		_, open := <-ech
+		assert.False(t, open, "o canal de erro deve receber um único valor")
+	})
+}
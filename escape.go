@@ -0,0 +1,76 @@
+package fflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// O ffmpeg interpreta um filtergraph em dois níveis: primeiro a descrição de cada filtro
+// dentro do graph (separadores ' \ [ ] , ;) e depois o valor de cada opção dentro do
+// filtro (separadores ' \ :). AtomicFilter.String aplica o escape do graph; o escape
+// dos valores é feito por EscapeValue e Param.
+//
+// ffmpeg parses a filtergraph in two levels: first each filter description inside the
+// graph (separators ' \ [ ] , ;) and then each option value inside the filter
+// (separators ' \ :). AtomicFilter.String applies the graph escaping; value escaping
+// is done by EscapeValue and Param.
+const (
+	valueSpecialChars = `\':`
+	graphSpecialChars = `\'[],;`
+)
+
+// Literal marca um valor de opção já escapado, que Param usa sem alterações.
+//
+// Literal marks an already escaped option value, which Param uses unchanged.
+type Literal string
+
+// EscapeValue escapa um valor para uso como opção dentro de um filtro,
+// ex.: o texto do drawtext ou um caminho do Windows (C:\fonts\arial.ttf).
+//
+// EscapeValue escapes a value for use as an option inside a filter,
+// e.g. the drawtext text or a Windows path (C:\fonts\arial.ttf).
+func EscapeValue(value string) string {
+	return escapeChars(value, valueSpecialChars)
+}
+
+// Param monta um parâmetro nomeado (chave=valor) para AtomicFilter.Params.
+// Strings são escapadas com EscapeValue; Literal é usado como está; outros tipos
+// são formatados com fmt.
+// Exemplo: `Param("text", "Hora: 10:00")` -> `text=Hora\: 10\:00`
+//
+// Param builds a named parameter (key=value) for AtomicFilter.Params.
+// Strings are escaped with EscapeValue; Literal is used as is; other types
+// are formatted with fmt.
+// Example: `Param("text", "Time: 10:00")` -> `text=Time\: 10\:00`
+func Param(key string, value any) string {
+	switch v := value.(type) {
+	case Literal:
+		return key + "=" + string(v)
+	case string:
+		return key + "=" + EscapeValue(v)
+	default:
+		return key + "=" + EscapeValue(fmt.Sprint(v))
+	}
+}
+
+// escapeGraph escapa a descrição de um filtro para uso dentro de um filtergraph.
+//
+// escapeGraph escapes a filter description for use inside a filtergraph.
+func escapeGraph(desc string) string {
+	return escapeChars(desc, graphSpecialChars)
+}
+
+func escapeChars(value, special string) string {
+	if !strings.ContainsAny(value, special) {
+		return value
+	}
+
+	var sb strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(special, r) {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,164 @@
+package fflow
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// ExecSpec descreve o processo que o Executor deve iniciar.
+//
+// ExecSpec describes the process the Executor must start.
+type ExecSpec struct {
+	Path string
+	Args []string
+	Env  []string
+	Dir  string
+
+	// Stdin é a entrada padrão do processo (InputReader com pipe:0), ou nil.
+	//
+	// Stdin is the process standard input (InputReader with pipe:0), or nil.
+	Stdin io.Reader
+
+	// ExtraFiles são herdados pelo processo a partir do fd 3 (pipe:3, pipe:4...).
+	// Em RunWithProgress, o último é o destino do -progress, exceto no Windows e com
+	// Executors que não os suportam (ver ExtraFilesSupporter). O chamador fecha suas
+	// cópias depois que Start retorna.
+	//
+	// ExtraFiles are inherited by the process starting at fd 3 (pipe:3, pipe:4...).
+	// In RunWithProgress, the last one is the -progress target, except on Windows and
+	// with Executors that do not support them (see ExtraFilesSupporter). The caller
+	// closes its copies after Start returns.
+	ExtraFiles []*os.File
+
+	// CancelMode e GracePeriod definem como o processo é encerrado quando o contexto
+	// é cancelado (ver WithGracefulCancel).
+	//
+	// CancelMode and GracePeriod define how the process is stopped when the context
+	// is cancelled (see WithGracefulCancel).
+	CancelMode  CancelMode
+	GracePeriod time.Duration
+}
+
+// Executor inicia os processos do ffmpeg. O padrão usa os/exec,
+// mas pode ser substituído (wrappers de container, fakes em testes etc.).
+// Um Executor que não repassa ExecSpec.ExtraFiles ao processo (ex.: ssh, docker exec)
+// deve implementar ExtraFilesSupporter retornando false; RunWithProgress passa então
+// a ler o progresso do stderr.
+//
+// Executor starts ffmpeg processes. The default uses os/exec,
+// but it can be replaced (container wrappers, fakes in tests, etc.).
+// An Executor that does not forward ExecSpec.ExtraFiles to the process (e.g. ssh,
+// docker exec) must implement ExtraFilesSupporter returning false; RunWithProgress
+// then reads progress from stderr.
+type Executor interface {
+	// Start inicia o processo descrito por spec. O processo deve ser
+	// encerrado quando ctx for cancelado.
+	//
+	// Start starts the process described by spec. The process must be
+	// terminated when ctx is cancelled.
+	Start(ctx context.Context, spec ExecSpec) (Process, error)
+}
+
+// ExtraFilesSupporter pode ser implementada por um Executor para informar se ele
+// repassa ExecSpec.ExtraFiles. Executors que não a implementam são tratados como
+// compatíveis. Sem ExtraFiles, o -progress vai para o stderr (pipe:2), como no Windows,
+// e os pipes extras de InputReader e OutputWriter não funcionam.
+//
+// ExtraFilesSupporter may be implemented by an Executor to report whether it forwards
+// ExecSpec.ExtraFiles. Executors that do not implement it are treated as supporting
+// them. Without ExtraFiles, -progress goes to stderr (pipe:2), as on Windows, and the
+// extra pipes of InputReader and OutputWriter do not work.
+type ExtraFilesSupporter interface {
+	SupportsExtraFiles() bool
+}
+
+// supportsExtraFiles informa se e repassa ExecSpec.ExtraFiles (ver ExtraFilesSupporter).
+//
+// supportsExtraFiles reports whether e forwards ExecSpec.ExtraFiles (see ExtraFilesSupporter).
+func supportsExtraFiles(e Executor) bool {
+	s, ok := e.(ExtraFilesSupporter)
+	return !ok || s.SupportsExtraFiles()
+}
+
+// Process representa um processo iniciado por um Executor.
+//
+// Process represents a process started by an Executor.
+type Process interface {
+	// Stdout retorna a saída padrão do processo.
+	//
+	// Stdout returns the process standard output.
+	Stdout() io.Reader
+
+	// Stderr retorna a saída de erro do processo.
+	//
+	// Stderr returns the process standard error.
+	Stderr() io.Reader
+
+	// Wait aguarda o término do processo. Deve ser chamado somente depois
+	// que Stdout e Stderr forem lidos até o EOF.
+	//
+	// Wait waits for the process to exit. It must only be called after
+	// Stdout and Stderr have been read until EOF.
+	Wait() error
+}
+
+// argv retorna o comando completo, incluindo o binário.
+//
+// argv returns the full command, including the binary.
+func (s ExecSpec) argv() []string {
+	return append([]string{s.Path}, s.Args...)
+}
+
+type execExecutor struct{}
+
+func (execExecutor) Start(ctx context.Context, spec ExecSpec) (Process, error) {
+	cmd := newExecCmd(ctx, spec)
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return &execProcess{cmd: cmd, stdout: stdout, stderr: stderr}, nil
+}
+
+type execProcess struct {
+	cmd    *exec.Cmd
+	stdout io.Reader
+	stderr io.Reader
+}
+
+func (p *execProcess) Stdout() io.Reader { return p.stdout }
+func (p *execProcess) Stderr() io.Reader { return p.stderr }
+func (p *execProcess) Wait() error       { return p.cmd.Wait() }
+
+func newExecCmd(ctx context.Context, spec ExecSpec) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, spec.Path, spec.Args...)
+	cmd.Dir = spec.Dir
+	cmd.Stdin = spec.Stdin
+	cmd.ExtraFiles = spec.ExtraFiles
+	setCancel(cmd, spec)
+	if len(spec.Env) > 0 {
+		cmd.Env = append(os.Environ(), spec.Env...)
+	}
+	return cmd
+}
+
+// WithExecutor substitui o Executor usado por Run e RunWithProgress.
+//
+// WithExecutor replaces the Executor used by Run and RunWithProgress.
+func WithExecutor(e Executor) Option {
+	return func(b *ffmpegBuilder) { b.executor = e }
+}
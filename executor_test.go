@@ -0,0 +1,189 @@
+package fflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExecutor struct {
+	spec     ExecSpec
+	stdout   string
+	stderr   string
+	progress string
+	err      error
+}
+
+func (f *fakeExecutor) Start(_ context.Context, spec ExecSpec) (Process, error) {
+	f.spec = spec
+	if f.progress != "" {
+		// O destino do -progress é o último dos ExtraFiles.
+		if _, err := spec.ExtraFiles[len(spec.ExtraFiles)-1].WriteString(f.progress); err != nil {
+			return nil, err
+		}
+	}
+	return &fakeProcess{
+		stdout: strings.NewReader(f.stdout),
+		stderr: strings.NewReader(f.stderr),
+		err:    f.err,
+	}, nil
+}
+
+type fakeProcess struct {
+	stdout io.Reader
+	stderr io.Reader
+	err    error
+}
+
+func (p *fakeProcess) Stdout() io.Reader { return p.stdout }
+func (p *fakeProcess) Stderr() io.Reader { return p.stderr }
+func (p *fakeProcess) Wait() error       { return p.err }
+
+// noExtraFilesExecutor simula um Executor que não repassa ExtraFiles (ex.: ssh).
+type noExtraFilesExecutor struct{ *fakeExecutor }
+
+func (noExtraFilesExecutor) SupportsExtraFiles() bool { return false }
+
+func TestExecutor(t *testing.T) {
+	t.Run("Run usa o Executor injetado", func(t *testing.T) {
+		fake := &fakeExecutor{}
+		err := New(WithExecutor(fake)).
+			Input("in.mp4").
+			Output("out.mp4").
+			Command().
+			Run(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, "ffmpeg", fake.spec.Path)
+		assert.Equal(t, []string{"-loglevel", "error", "-y", "-i", "in.mp4", "out.mp4"}, fake.spec.Args)
+	})
+
+	t.Run("Run retorna o erro do processo", func(t *testing.T) {
+		fake := &fakeExecutor{err: errors.New("exit status 1")}
+		err := New(WithExecutor(fake)).Input("in.mp4").Output("out.mp4").Command().Run(context.Background())
+
+		assert.ErrorIs(t, err, fake.err)
+	})
+
+	t.Run("RunWithProgressFunc emite eventos do Executor", func(t *testing.T) {
+		fake := &fakeExecutor{progress: "frame=10\nfps=25.0\nout_time=00:00:01.500000\nspeed=1.5x\nprogress=continue\n" +
+			"frame=20\nprogress=end\n"}
+
+		var events []Progress
+		err := New(WithExecutor(fake)).
+			Input("in.mp4").
+			Output("out.mp4").
+			Command().
+			RunWithProgressFunc(context.Background(), func(p Progress) { events = append(events, p) })
+
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, 10, events[0].Frame)
+		assert.Equal(t, 1500*time.Millisecond, events[0].OutTime)
+		assert.Equal(t, "1.5x", events[0].Speed)
+		assert.Equal(t, 20, events[1].Frame)
+		assert.Contains(t, fake.spec.Args, "-progress")
+	})
+
+	t.Run("RunWithProgress não bloqueia sem consumidor", func(t *testing.T) {
+		var progress strings.Builder
+		for i := 1; i <= 100; i++ {
+			fmt.Fprintf(&progress, "frame=%d\nprogress=continue\n", i)
+		}
+		fake := &fakeExecutor{progress: progress.String() + "frame=101\nprogress=end\n"}
+
+		pch, ech := New(WithExecutor(fake)).Input("in.mp4").Output("out.mp4").Command().RunWithProgress(context.Background())
+
+		select {
+		case err := <-ech:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("RunWithProgress bloqueou esperando o consumidor")
+		}
+
+		var last Progress
+		for p := range pch {
+			last = p
+		}
+		assert.Equal(t, 101, last.Frame)
+	})
+
+	t.Run("Progresso e log usam streams separados", func(t *testing.T) {
+		fake := &fakeExecutor{
+			stderr: "[h264 @ 0x1] invalid NAL unit size (0 > 12).\n" +
+				"Error while decoding stream #0:0: Invalid data found when processing input\n" +
+				"speed=1x\n",
+			progress: "frame=5\nprogress=end\n",
+		}
+
+		var events []Progress
+		var logs []LogLine
+		err := New(WithExecutor(fake), WithLogFunc(func(l LogLine) { logs = append(logs, l) })).
+			Input("in.mp4").
+			Output("out.mp4").
+			Command().
+			RunWithProgressFunc(context.Background(), func(p Progress) { events = append(events, p) })
+
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, 5, events[0].Frame)
+		assert.Equal(t, []string{"-progress", "pipe:3", "-nostats"}, fake.spec.Args[len(fake.spec.Args)-3:])
+
+		require.Len(t, logs, 3)
+		assert.Equal(t, LogLine{
+			Component: "h264 @ 0x1",
+			Message:   "invalid NAL unit size (0 > 12).",
+			Raw:       "[h264 @ 0x1] invalid NAL unit size (0 > 12).",
+		}, logs[0])
+		assert.Equal(t, "", logs[1].Component)
+		assert.Equal(t, "speed=1x", logs[2].Message)
+	})
+
+	t.Run("Executor sem ExtraFiles recebe o progresso no stderr", func(t *testing.T) {
+		fake := &fakeExecutor{stderr: "[h264 @ 0x1] invalid NAL unit size (0 > 12).\nframe=5\nprogress=end\n"}
+
+		var events []Progress
+		var logs []LogLine
+		err := New(WithExecutor(noExtraFilesExecutor{fake}), WithLogFunc(func(l LogLine) { logs = append(logs, l) })).
+			Input("in.mp4").
+			Output("out.mp4").
+			Command().
+			RunWithProgressFunc(context.Background(), func(p Progress) { events = append(events, p) })
+
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, 5, events[0].Frame)
+		assert.Equal(t, []string{"-progress", "pipe:2", "-nostats"}, fake.spec.Args[len(fake.spec.Args)-3:])
+		assert.Empty(t, fake.spec.ExtraFiles)
+		require.Len(t, logs, 1)
+		assert.Equal(t, "h264 @ 0x1", logs[0].Component)
+	})
+
+	t.Run("Run envia o stderr para WithLogFunc", func(t *testing.T) {
+		fake := &fakeExecutor{stderr: "[libx264 @ 0x2] using cpu capabilities: SSE2\n"}
+
+		var logs []LogLine
+		err := New(WithExecutor(fake), WithLogFunc(func(l LogLine) { logs = append(logs, l) })).
+			Input("in.mp4").
+			Output("out.mp4").
+			Command().
+			Run(context.Background())
+
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, "libx264 @ 0x2", logs[0].Component)
+		assert.Equal(t, "using cpu capabilities: SSE2", logs[0].Message)
+	})
+
+	t.Run("Cmd usa os/exec com o binário ffmpeg", func(t *testing.T) {
+		cmd := New().Input("in.mp4").Output("out.mp4").Command().Cmd(context.Background())
+		assert.Equal(t, []string{"ffmpeg", "-loglevel", "error", "-y", "-i", "in.mp4", "out.mp4"}, cmd.Args)
+	})
+}
@@ -1,32 +1,240 @@
 // Package fflow fornece um builder fluente para compor comandos FFmpeg.
 package fflow
 
+import (
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultBinary é o binário do ffmpeg usado pelos builders criados sem WithBinary.
+// É inicializado a partir da variável de ambiente FFMPEG_BIN, ou "ffmpeg" quando ela não está definida.
+//
+// DefaultBinary is the ffmpeg binary used by builders created without WithBinary.
+// It is initialized from the FFMPEG_BIN environment variable, or "ffmpeg" when it is unset.
+var DefaultBinary = defaultBinary()
+
 type StreamType string
 
 const (
-	Video    StreamType = "v"
-	Audio    StreamType = "a"
-	Subtitle StreamType = "s"
+	Video      StreamType = "v"
+	Audio      StreamType = "a"
+	Subtitle   StreamType = "s"
+	Data       StreamType = "d"
+	Attachment StreamType = "t"
 )
 
 type ffmpegBuilder struct {
-	beforeRead       []string
-	read             []string
-	write            []string
-	filters          []filter
-	simpleFilterFlag string
-	output           string
+	global          []string
+	pending         []string
+	inputs          []*input
+	filters         []filter
+	padSeq          int
+	graphErr        error
+	outputs         []*output
+	executor        Executor
+	binary          string
+	env             []string
+	dir             string
+	stderrLines     int
+	duration        time.Duration
+	scriptFlag      FilterScriptFlag
+	scriptThreshold int
+	caps            *Capabilities
+	stdin           io.Reader
+	stdout          io.Writer
+	extraPipes      []pipeStream
+	cancelMode      CancelMode
+	grace           time.Duration
+	logFunc         func(LogLine)
+	logger          *slog.Logger
+}
+
+// input guarda as opções e o arquivo de um input do comando.
+//
+// input holds the options and the file of a command input.
+type input struct {
+	args []string
+	path string
+}
+
+// output guarda as opções e o arquivo de um output do comando.
+//
+// output holds the options and the file of a command output.
+type output struct {
+	args    []string
+	filters []*simpleChain
+	path    string
+}
+
+// simpleChain é uma cadeia de filtros simples de um output, emitida com sua flag (-vf, -filter:a:0...).
+//
+// simpleChain is a simple filter chain of an output, emitted with its flag (-vf, -filter:a:0...).
+type simpleChain struct {
+	flag    SimpleFilterType
+	filters []AtomicFilter
+}
+
+func (c *simpleChain) String() string {
+	parts := make([]string, len(c.filters))
+	for i, f := range c.filters {
+		parts[i] = f.String()
+	}
+	return strings.Join(parts, ",")
 }
 
+// chain retorna a cadeia do output para flag, criando-a se necessário.
+//
+// chain returns the output chain for flag, creating it if needed.
+func (o *output) chain(flag SimpleFilterType) *simpleChain {
+	for _, c := range o.filters {
+		if c.flag == flag {
+			return c
+		}
+	}
+	c := &simpleChain{flag: flag}
+	o.filters = append(o.filters, c)
+	return c
+}
+
+// Option configura o builder criado por New.
+//
+// Option configures the builder created by New.
+type Option func(*ffmpegBuilder)
+
 // New inicia um novo construtor de comando FFmpeg, retornando uma GlobalStage.
 // Este é o ponto de entrada para construir qualquer comando FFmpeg, permitindo a configuração de opções globais
-// antes de especificar as entradas.
+// antes de especificar as entradas. Options opcionais configuram a execução do comando.
 //
 // New starts a new FFmpeg command builder, returning a GlobalStage.
 // This is the entry point for building any FFmpeg command, allowing the configuration of global options
-// before specifying inputs.
-func New() *beforeReadCtx {
-	return &beforeReadCtx{
-		b: &ffmpegBuilder{beforeRead: []string{"-loglevel", "error", "-y"}},
+// before specifying inputs. Optional Options configure how the command is executed.
+func New(opts ...Option) *beforeReadCtx {
+	return &beforeReadCtx{b: newBuilder([]string{"-loglevel", "error", "-y"}, opts...)}
+}
+
+func newBuilder(global []string, opts ...Option) *ffmpegBuilder {
+	b := &ffmpegBuilder{
+		global:   global,
+		executor: execExecutor{},
+		binary:   DefaultBinary,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// WithBinary define o caminho do binário do ffmpeg usado pelo builder.
+//
+// WithBinary sets the path of the ffmpeg binary used by the builder.
+func WithBinary(path string) Option {
+	return func(b *ffmpegBuilder) { b.binary = path }
+}
+
+// WithEnv adiciona variáveis de ambiente ("CHAVE=valor") ao processo do ffmpeg,
+// além das herdadas do processo atual.
+//
+// WithEnv adds environment variables ("KEY=value") to the ffmpeg process,
+// on top of the ones inherited from the current process.
+func WithEnv(vars ...string) Option {
+	return func(b *ffmpegBuilder) { b.env = append(b.env, vars...) }
+}
+
+// WithDir define o diretório de trabalho do processo do ffmpeg.
+//
+// WithDir sets the working directory of the ffmpeg process.
+func WithDir(dir string) Option {
+	return func(b *ffmpegBuilder) { b.dir = dir }
+}
+
+// clone retorna uma cópia profunda do builder, que pode ser alterada
+// sem afetar o original. stdin, stdout e os streams de extraPipes são compartilhados.
+//
+// clone returns a deep copy of the builder, which can be modified
+// without affecting the original. stdin, stdout and the extraPipes streams are shared.
+func (b *ffmpegBuilder) clone() *ffmpegBuilder {
+	c := *b
+	c.global = slices.Clone(b.global)
+	c.pending = slices.Clone(b.pending)
+	c.filters = slices.Clone(b.filters)
+	c.env = slices.Clone(b.env)
+	c.extraPipes = slices.Clone(b.extraPipes)
+
+	c.inputs = make([]*input, len(b.inputs))
+	for i, in := range b.inputs {
+		c.inputs[i] = &input{args: slices.Clone(in.args), path: in.path}
+	}
+
+	c.outputs = make([]*output, len(b.outputs))
+	for i, o := range b.outputs {
+		c.outputs[i] = &output{args: slices.Clone(o.args), path: o.path}
+		for _, sc := range o.filters {
+			c.outputs[i].filters = append(c.outputs[i].filters, &simpleChain{flag: sc.flag, filters: slices.Clone(sc.filters)})
+		}
+	}
+	return &c
+}
+
+// addInput registra um novo input, consumindo as opções pendentes
+// definidas antes do -i (Ss, T, To do GlobalStage).
+//
+// addInput registers a new input, consuming the pending options
+// set before -i (Ss, T, To of the GlobalStage).
+func (b *ffmpegBuilder) addInput(path string, opts ...InputOption) {
+	in := &input{args: b.pending, path: path}
+	b.pending = nil
+	for _, opt := range opts {
+		opt(in)
+	}
+	b.inputs = append(b.inputs, in)
+}
+
+// currentOutput retorna o output mais recente, criando o primeiro se necessário.
+//
+// currentOutput returns the most recent output, creating the first one if needed.
+func (b *ffmpegBuilder) currentOutput() *output {
+	if len(b.outputs) == 0 {
+		return b.newOutput()
+	}
+	return b.outputs[len(b.outputs)-1]
+}
+
+// openOutput retorna o output atual se ele ainda não tiver arquivo, ou inicia um novo.
+//
+// openOutput returns the current output if it has no file yet, or starts a new one.
+func (b *ffmpegBuilder) openOutput() *output {
+	o := b.currentOutput()
+	if o.path != "" {
+		o = b.newOutput()
+	}
+	return o
+}
+
+func (b *ffmpegBuilder) newOutput() *output {
+	o := &output{}
+	b.outputs = append(b.outputs, o)
+	return o
+}
+
+func (b *ffmpegBuilder) addOutputArgs(args ...string) {
+	o := b.currentOutput()
+	o.args = append(o.args, args...)
+}
+
+func (b *ffmpegBuilder) binaryPath() string {
+	if b.binary == "" {
+		return DefaultBinary
+	}
+	return b.binary
+}
+
+func defaultBinary() string {
+	if bin := os.Getenv("FFMPEG_BIN"); bin != "" {
+		return bin
 	}
+	return "ffmpeg"
 }
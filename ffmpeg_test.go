@@ -1,6 +1,9 @@
 package fflow
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -30,3 +33,94 @@ func TestFFmpegBuilder(t *testing.T) {
 		assert.NotNil(t, f2)
 	})
 }
+
+func TestBuilderOptions(t *testing.T) {
+	t.Run("WithBinary alimenta Build e Cmd", func(t *testing.T) {
+		b := New(WithBinary("/opt/ffmpeg-nvenc/bin/ffmpeg")).Input("in.mp4").Output("out.mp4")
+
+		assert.Equal(t, "/opt/ffmpeg-nvenc/bin/ffmpeg -loglevel error -y -i in.mp4 out.mp4", b.Build())
+
+		cmd := b.Command().Cmd(context.Background())
+		assert.Equal(t, "/opt/ffmpeg-nvenc/bin/ffmpeg", cmd.Path)
+	})
+
+	t.Run("WithEnv e WithDir alimentam Cmd", func(t *testing.T) {
+		cmd := New(WithEnv("CUDA_VISIBLE_DEVICES=1"), WithDir("/tmp")).
+			Input("in.mp4").
+			Output("out.mp4").
+			Command().
+			Cmd(context.Background())
+
+		assert.Equal(t, "/tmp", cmd.Dir)
+		assert.Contains(t, cmd.Env, "CUDA_VISIBLE_DEVICES=1")
+	})
+
+	t.Run("WithEnv e WithDir chegam ao Executor", func(t *testing.T) {
+		fake := &fakeExecutor{}
+		err := New(WithExecutor(fake), WithBinary("ffmpeg-static"), WithEnv("A=1"), WithDir("/work")).
+			Input("in.mp4").
+			Output("out.mp4").
+			Command().
+			Run(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, "ffmpeg-static", fake.spec.Path)
+		assert.Equal(t, []string{"A=1"}, fake.spec.Env)
+		assert.Equal(t, "/work", fake.spec.Dir)
+	})
+
+	t.Run("FFMPEG_BIN define o binário padrão", func(t *testing.T) {
+		t.Setenv("FFMPEG_BIN", "/usr/local/bin/ffmpeg")
+		assert.Equal(t, "/usr/local/bin/ffmpeg", defaultBinary())
+
+		t.Setenv("FFMPEG_BIN", "")
+		assert.Equal(t, "ffmpeg", defaultBinary())
+	})
+}
+
+func TestClone(t *testing.T) {
+	t.Run("Clone deriva comandos de um builder base", func(t *testing.T) {
+		base := New().Input("in.mp4").Output("out.mp4").VideoCodec("libx264")
+
+		hd := base.Clone().CRF(18)
+		sd := base.Clone().CRF(28)
+
+		assert.Equal(t, "ffmpeg -loglevel error -y -i in.mp4 -c:v libx264 out.mp4", base.Build())
+		assert.Equal(t, "ffmpeg -loglevel error -y -i in.mp4 -c:v libx264 -crf 18 out.mp4", hd.Build())
+		assert.Equal(t, "ffmpeg -loglevel error -y -i in.mp4 -c:v libx264 -crf 28 out.mp4", sd.Build())
+	})
+
+	t.Run("Clone em todos os estágios", func(t *testing.T) {
+		global := New()
+		globalClone := global.Clone().Raw("-hide_banner")
+		assert.NotContains(t, global.Input("a.mp4").Output("o.mp4").Build(), "-hide_banner")
+		assert.Contains(t, globalClone.Input("a.mp4").Output("o.mp4").Build(), "-hide_banner")
+
+		read := New().Input("a.mp4")
+		read.Clone().Input("b.mp4")
+		assert.Equal(t, "ffmpeg -loglevel error -y -i a.mp4 o.mp4", read.Output("o.mp4").Build())
+
+		simple := New().Input("a.mp4").Filter().Simple(FilterVideo).Add(AtomicFilter{Name: "hflip"})
+		simple.Clone().Add(AtomicFilter{Name: "vflip"})
+		assert.Equal(t, "ffmpeg -loglevel error -y -i a.mp4 -vf hflip o.mp4", simple.Done().Output("o.mp4").Build())
+
+		complex := New().Input("a.mp4").Filter().Complex()
+		complex.Clone().Chain([]string{"0:v"}, []AtomicFilter{{Name: "hflip"}}, []string{"v"})
+		assert.Equal(t, "ffmpeg -loglevel error -y -i a.mp4 o.mp4", complex.Done().Output("o.mp4").Build())
+	})
+
+	t.Run("Clones podem ser usados em goroutines", func(t *testing.T) {
+		base := New().Input("in.mp4").Output("out.mp4")
+
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				b := base.Clone().CRF(20 + i)
+				assert.Contains(t, b.Build(), fmt.Sprintf("-crf %d", 20+i))
+			}()
+		}
+		wg.Wait()
+	})
+}
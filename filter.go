@@ -5,9 +5,11 @@ import (
 	"strings"
 )
 
-// SimpleFilterType representa filtros simples aplicados a um único stream. FilterVideo para -vf ou FilterAudio para -af.
+// SimpleFilterType representa filtros simples aplicados a um único stream. FilterVideo para -vf,
+// FilterAudio para -af ou FilterStream para um stream específico (-filter:v:1).
 //
-// SimpleFilterType represents simple filters applied to a single stream. FilterVideo to -vf or FilterAudio to -af.
+// SimpleFilterType represents simple filters applied to a single stream. FilterVideo to -vf,
+// FilterAudio to -af or FilterStream for a specific stream (-filter:v:1).
 type SimpleFilterType string
 
 const (
@@ -22,6 +24,18 @@ const (
 	FilterAudio SimpleFilterType = "-af"
 )
 
+// FilterStream retorna a flag de filtro simples de um stream específico do output,
+// ex.: FilterStream(Video, 1) gera -filter:v:1. Um índice negativo omite o índice (-filter:a).
+//
+// FilterStream returns the simple filter flag of a specific output stream,
+// e.g. FilterStream(Video, 1) produces -filter:v:1. A negative index omits the index (-filter:a).
+func FilterStream(stream StreamType, index int) SimpleFilterType {
+	if index < 0 {
+		return SimpleFilterType(fmt.Sprintf("-filter:%s", stream))
+	}
+	return SimpleFilterType(fmt.Sprintf("-filter:%s:%d", stream, index))
+}
+
 type filter interface {
 	// String retorna a representação textual do filtro no formato aceito pelo ffmpeg.
 	//
@@ -34,11 +48,13 @@ type filter interface {
 	NeedsComplex() bool
 }
 type filterStage interface {
-	// Simple inicia a construção de filtros simples (-vf ou -af),
-	// aplicados diretamente a um único stream.
+	// Simple inicia a construção de filtros simples (-vf, -af, -filter:v:1...),
+	// aplicados diretamente a um único stream do output atual. Cada tipo tem sua
+	// própria cadeia; chamar Simple de novo com o mesmo tipo continua a cadeia existente.
 	//
-	// Simple starts building simple filters (-vf or -af),
-	// applied directly to a single stream.
+	// Simple starts building simple filters (-vf, -af, -filter:v:1...),
+	// applied directly to a single stream of the current output. Each type has its
+	// own chain; calling Simple again with the same type continues the existing chain.
 	Simple(t SimpleFilterType) simpleFilter
 
 	// Complex inicia a construção de filtros complexos (-filter_complex),
@@ -47,13 +63,18 @@ type filterStage interface {
 	// Complex starts building complex filters (-filter_complex),
 	// allowing multiple inputs and outputs.
 	Complex() complexFilter
+
+	// Clone retorna uma cópia independente deste estágio (ver beforeReadStage.Clone).
+	//
+	// Clone returns an independent copy of this stage (see beforeReadStage.Clone).
+	Clone() filterStage
 }
 
 type simpleFilter interface {
-	// Add adiciona um filtro atômico à cadeia de filtros simples.
+	// Add adiciona um ou mais filtros atômicos à cadeia de filtros simples.
 	//
-	// Add appends an atomic filter to the simple filter chain.
-	Add(filter AtomicFilter) simpleFilter
+	// Add appends one or more atomic filters to the simple filter chain.
+	Add(filters ...AtomicFilter) simpleFilter
 
 	// Done finaliza a construção dos filtros simples
 	// e avança para o próximo estágio do pipeline.
@@ -61,6 +82,11 @@ type simpleFilter interface {
 	// Done finalizes the simple filter construction
 	// and advances to the next pipeline stage.
 	Done() writeStage
+
+	// Clone retorna uma cópia independente deste estágio (ver beforeReadStage.Clone).
+	//
+	// Clone returns an independent copy of this stage (see beforeReadStage.Clone).
+	Clone() simpleFilter
 }
 
 type complexFilter interface {
@@ -71,31 +97,72 @@ type complexFilter interface {
 	// explicitly connecting inputs and outputs.
 	Chain(in []string, filter []AtomicFilter, out []string) complexFilter
 
+	// Link adiciona uma cadeia conectando pads em vez de labels escritos à mão.
+	// Cada ponteiro em out recebe um Pad com label único gerado automaticamente,
+	// que pode ser usado em cadeias seguintes ou em writeStage.MapPad. Um ponteiro nil
+	// é reportado por Err e Validate.
+	// Exemplo:
+	//   var scaled, out fflow.Pad
+	//   Link([]fflow.Pad{fflow.InputPad(0, fflow.Video)}, scale, &scaled).
+	//   Link([]fflow.Pad{scaled, fflow.InputPad(1, fflow.Video)}, overlay, &out)
+	//
+	// Link adds a chain connecting pads instead of hand-written labels.
+	// Each pointer in out receives a Pad with an automatically generated unique label,
+	// which can be used in later chains or in writeStage.MapPad. A nil pointer
+	// is reported by Err and Validate.
+	Link(in []Pad, filter []AtomicFilter, out ...*Pad) complexFilter
+
+	// Err retorna o primeiro erro de ligação encontrado por Chain e Link (labels inválidos,
+	// inputs inexistentes, labels produzidos ou consumidos duas vezes) ou, depois de Done,
+	// por labels consumidos que nenhuma cadeia produz.
+	//
+	// Err returns the first wiring error found by Chain and Link (invalid labels, missing
+	// inputs, labels produced or consumed twice) or, after Done, by consumed labels that
+	// no chain produces.
+	Err() error
+
 	// Done finaliza a construção dos filtros complexos
-	// e avança para o próximo estágio do pipeline.
+	// e avança para o próximo estágio do pipeline. Done confere os labels consumidos
+	// que nenhuma cadeia produz (ver Err); writeStage.Validate, chamado por Run,
+	// reporta todos os erros de ligação, incluindo labels que não são mapeados.
 	//
 	// Done finalizes the complex filter construction
-	// and advances to the next pipeline stage.
+	// and advances to the next pipeline stage. Done checks for consumed labels that
+	// no chain produces (see Err); writeStage.Validate, called by Run, reports every
+	// wiring error, including labels that are not mapped.
 	Done() writeStage
+
+	// Clone retorna uma cópia independente deste estágio (ver beforeReadStage.Clone).
+	//
+	// Clone returns an independent copy of this stage (see beforeReadStage.Clone).
+	Clone() complexFilter
 }
 
 type (
-	filterCtx        struct{ b *ffmpegBuilder }
-	simpleFilterCtx  struct{ b *ffmpegBuilder }
+	filterCtx       struct{ b *ffmpegBuilder }
+	simpleFilterCtx struct {
+		b    *ffmpegBuilder
+		flag SimpleFilterType
+	}
 	complexFilterCtx struct{ b *ffmpegBuilder }
 )
 
 func (c *filterCtx) Simple(t SimpleFilterType) simpleFilter {
-	c.b.simpleFilterFlag = string(t)
-	return &simpleFilterCtx{c.b}
+	c.b.currentOutput().chain(t)
+	return &simpleFilterCtx{b: c.b, flag: t}
 }
 
 func (c *filterCtx) Complex() complexFilter {
 	return &complexFilterCtx{c.b}
 }
 
-func (sf *simpleFilterCtx) Add(filter AtomicFilter) simpleFilter {
-	sf.b.filters = append(sf.b.filters, filter)
+func (c *filterCtx) Clone() filterStage {
+	return &filterCtx{c.b.clone()}
+}
+
+func (sf *simpleFilterCtx) Add(filters ...AtomicFilter) simpleFilter {
+	chain := sf.b.currentOutput().chain(sf.flag)
+	chain.filters = append(chain.filters, filters...)
 	return sf
 }
 
@@ -103,26 +170,74 @@ func (sf *simpleFilterCtx) Done() writeStage {
 	return &writeCtx{sf.b}
 }
 
+func (sf *simpleFilterCtx) Clone() simpleFilter {
+	return &simpleFilterCtx{b: sf.b.clone(), flag: sf.flag}
+}
+
 func (cf *complexFilterCtx) Chain(in []string, filter []AtomicFilter, out []string) complexFilter {
 	chain := Chain{Inputs: in, Filter: filter, Output: out}
+	if cf.b.graphErr == nil {
+		cf.b.graphErr = cf.b.graph().check(chain, len(cf.b.inputs))
+	}
 	cf.b.filters = append(cf.b.filters, chain)
 	return cf
 }
 
+func (cf *complexFilterCtx) Link(in []Pad, filter []AtomicFilter, out ...*Pad) complexFilter {
+	labels := make([]string, len(out))
+	var nilPad error
+	for i, p := range out {
+		labels[i] = cf.b.newLabel()
+		if p == nil {
+			// INFO: o label é gerado mesmo assim para manter a posição das saídas seguintes;
+			// como ninguém pode referenciá-lo, Validate também o reporta como não mapeado.
+			if nilPad == nil {
+				nilPad = fmt.Errorf("graph: Link output %d is a nil *Pad; label [%s] cannot be referenced", i, labels[i])
+			}
+			continue
+		}
+		*p = Pad{label: labels[i]}
+	}
+
+	cf.Chain(padLabels(in), filter, labels)
+	if cf.b.graphErr == nil {
+		cf.b.graphErr = nilPad
+	}
+	return cf
+}
+
+func (cf *complexFilterCtx) Err() error {
+	return cf.b.graphErr
+}
+
 func (cf *complexFilterCtx) Done() writeStage {
+	if cf.b.graphErr == nil {
+		cf.b.graphErr = cf.b.graph().unresolved()
+	}
 	return &writeCtx{cf.b}
 }
 
+func (cf *complexFilterCtx) Clone() complexFilter {
+	return &complexFilterCtx{cf.b.clone()}
+}
+
+// AtomicFilter representa um único filtro do ffmpeg. Cada item de Params é uma opção
+// já no formato do filtro ("valor" ou "chave=valor"); use Param ou EscapeValue para
+// escapar valores com caracteres especiais. O escape do filtergraph é aplicado por String.
+//
+// AtomicFilter represents a single ffmpeg filter. Each Params item is an option
+// already in the filter format ("value" or "key=value"); use Param or EscapeValue to
+// escape values with special characters. The filtergraph escaping is applied by String.
 type AtomicFilter struct {
-	Name   string
-	Params []string
+	Name   string   `json:"name" yaml:"name"`
+	Params []string `json:"params,omitempty" yaml:"params,omitempty"`
 }
 
 func (f AtomicFilter) String() string {
 	if len(f.Params) == 0 {
 		return f.Name
 	}
-	return fmt.Sprintf("%s=%s", f.Name, strings.Join(f.Params, ":"))
+	return fmt.Sprintf("%s=%s", f.Name, escapeGraph(strings.Join(f.Params, ":")))
 }
 
 func (f AtomicFilter) NeedsComplex() bool {
@@ -130,9 +245,9 @@ func (f AtomicFilter) NeedsComplex() bool {
 }
 
 type Chain struct {
-	Inputs []string
-	Filter []AtomicFilter
-	Output []string
+	Inputs []string       `json:"inputs,omitempty" yaml:"inputs,omitempty"`
+	Filter []AtomicFilter `json:"filters" yaml:"filters"`
+	Output []string       `json:"outputs,omitempty" yaml:"outputs,omitempty"`
 }
 
 func (c Chain) String() string {
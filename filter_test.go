@@ -144,6 +144,62 @@ func TestFilterElements(t *testing.T) {
 	})
 }
 
+func TestFilterEscaping(t *testing.T) {
+	t.Run("EscapeValue e Param", func(t *testing.T) {
+		assert.Equal(t, `Time\: 10\:00`, EscapeValue("Time: 10:00"))
+		assert.Equal(t, `C\:\\fonts\\arial.ttf`, EscapeValue(`C:\fonts\arial.ttf`))
+		assert.Equal(t, `it\'s`, EscapeValue("it's"))
+
+		assert.Equal(t, `text=a\:b`, Param("text", "a:b"))
+		assert.Equal(t, `text=a\:b`, Param("text", Literal(`a\:b`)))
+		assert.Equal(t, "fontsize=42", Param("fontsize", 42))
+	})
+
+	t.Run("AtomicFilter escapa a descrição no filtergraph", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			filter   AtomicFilter
+			expected string
+		}{
+			{
+				name:     "Texto com vírgula, colchetes e ponto e vírgula",
+				filter:   AtomicFilter{Name: "drawtext", Params: []string{Param("text", "a, [b]; c")}},
+				expected: `drawtext=text=a\, \[b\]\; c`,
+			},
+			{
+				name:     "Texto com dois pontos e aspas",
+				filter:   AtomicFilter{Name: "drawtext", Params: []string{Param("text", "it's 10:00")}},
+				expected: `drawtext=text=it\\\'s 10\\:00`,
+			},
+			{
+				name:     "Caminho do Windows",
+				filter:   AtomicFilter{Name: "subtitles", Params: []string{Param("filename", `C:\subs\a.srt`)}},
+				expected: `subtitles=filename=C\\:\\\\subs\\\\a.srt`,
+			},
+			{
+				name:     "Parâmetros sem caracteres especiais não mudam",
+				filter:   AtomicFilter{Name: "overlay", Params: []string{"W-w-10:10"}},
+				expected: "overlay=W-w-10:10",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, tt.filter.String())
+			})
+		}
+	})
+
+	t.Run("Chain e Pipeline usam o filtro escapado", func(t *testing.T) {
+		c := Chain{
+			Inputs: []string{"0:v"},
+			Filter: []AtomicFilter{{Name: "drawtext", Params: []string{Param("text", "x,y")}}},
+			Output: []string{"out"},
+		}
+		assert.Equal(t, `[0:v]drawtext=text=x\,y[out]`, c.String())
+	})
+}
+
 func TestFilterStages(t *testing.T) {
 	t.Run("filterCtx (Entry Point)", func(t *testing.T) {
 		b := &ffmpegBuilder{}
@@ -166,17 +222,18 @@ func TestFilterStages(t *testing.T) {
 
 	t.Run("simpleFilterCtx (Simple Filter Builder)", func(t *testing.T) {
 		b := &ffmpegBuilder{}
-		sCtx := simpleFilterCtx{b}
+		sCtx := simpleFilterCtx{b: b, flag: FilterVideo}
 
-		t.Run("Add appends AtomicFilter to builder.filters", func(t *testing.T) { // Changed description
+		t.Run("Add appends AtomicFilter to the current output chain", func(t *testing.T) {
 			filter1 := AtomicFilter{Name: "scale", Params: []string{"1280", "-1"}}
 			filter2 := AtomicFilter{Name: "hflip"}
 
 			sCtx.Add(filter1)
 			sCtx.Add(filter2)
 
-			expectedFilters := []filter{filter1, filter2} // Now []Filter
-			assert.Equal(t, expectedFilters, b.filters)
+			expectedFilters := []AtomicFilter{filter1, filter2}
+			assert.Equal(t, expectedFilters, b.currentOutput().chain(FilterVideo).filters)
+			assert.Empty(t, b.filters)
 		})
 
 		t.Run("Done returns non-nil WriteStage", func(t *testing.T) {
@@ -220,3 +277,59 @@ func TestFilterStages(t *testing.T) {
 		})
 	})
 }
+
+func TestSimpleFilterChains(t *testing.T) {
+	scale := AtomicFilter{Name: "scale", Params: []string{"1280", "-2"}}
+	volume := AtomicFilter{Name: "volume", Params: []string{"0.5"}}
+
+	t.Run("FilterStream", func(t *testing.T) {
+		assert.Equal(t, SimpleFilterType("-filter:v:1"), FilterStream(Video, 1))
+		assert.Equal(t, SimpleFilterType("-filter:a:0"), FilterStream(Audio, 0))
+		assert.Equal(t, SimpleFilterType("-filter:a"), FilterStream(Audio, -1))
+	})
+
+	t.Run("Cadeias de vídeo e áudio separadas", func(t *testing.T) {
+		cmd := New().
+			Input("in.mp4").
+			Filter().
+			Simple(FilterVideo).
+			Add(scale).
+			Done().
+			Filter().
+			Simple(FilterAudio).
+			Add(volume).
+			Done().
+			Filter().
+			Simple(FilterVideo).
+			Add(AtomicFilter{Name: "hflip"}).
+			Done().
+			VideoCodec("libx264").
+			Output("out.mp4").
+			String()
+
+		assert.Equal(t, "-loglevel error -y -i in.mp4 -vf scale=1280:-2,hflip -af volume=0.5 -c:v libx264 out.mp4", cmd)
+	})
+
+	t.Run("Filtros por stream e por output", func(t *testing.T) {
+		w := New().
+			Input("in.mkv").
+			Filter().
+			Simple(FilterStream(Audio, 1)).
+			Add(volume).
+			Done().
+			Map("0").
+			Output("a.mkv").
+			NextOutput().
+			Filter().
+			Simple(FilterVideo).
+			Add(scale).
+			Done().
+			Output("b.mp4")
+
+		clone := w.Clone()
+		clone.Filter().Simple(FilterVideo).Add(AtomicFilter{Name: "hflip"})
+
+		assert.Equal(t, "-loglevel error -y -i in.mkv -filter:a:1 volume=0.5 -map 0 a.mkv -vf scale=1280:-2 b.mp4", w.String())
+		assert.Equal(t, "-loglevel error -y -i in.mkv -filter:a:1 volume=0.5 -map 0 a.mkv -vf scale=1280:-2,hflip b.mp4", clone.String())
+	})
+}
@@ -0,0 +1,384 @@
+package filters
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Marlliton/fflow"
+)
+
+// Volume multiplica o volume do áudio por factor (volume), ex.: 0.5 reduz pela metade.
+//
+// Volume multiplies the audio volume by factor (volume), e.g. 0.5 halves it.
+func Volume(factor float64) (fflow.AtomicFilter, error) {
+	if factor < 0 {
+		return fflow.AtomicFilter{}, errorf("volume", "factor must be >= 0")
+	}
+
+	var p params
+	p.add("volume", formatFloat(factor))
+	return p.filter("volume"), nil
+}
+
+// VolumeDB altera o volume do áudio em decibéis (volume), ex.: VolumeDB(-6).
+//
+// VolumeDB changes the audio volume in decibels (volume), e.g. VolumeDB(-6).
+func VolumeDB(gain Decibel) (fflow.AtomicFilter, error) {
+	var p params
+	p.add("volume", gain.String())
+	return p.filter("volume"), nil
+}
+
+// LoudnormOptions configura o filtro loudnorm (EBU R128). Campos zerados usam o padrão do ffmpeg.
+// Integrated em LUFS (-70 a -5), TruePeak em dBTP (-9 a 0) e LRA em LU (1 a 50).
+//
+// LoudnormOptions configures the loudnorm filter (EBU R128). Zero fields use the ffmpeg default.
+// Integrated in LUFS (-70 to -5), TruePeak in dBTP (-9 to 0) and LRA in LU (1 to 50).
+type LoudnormOptions struct {
+	Integrated float64
+	TruePeak   float64
+	LRA        float64
+}
+
+// Loudnorm normaliza a loudness do áudio (loudnorm).
+//
+// Loudnorm normalizes the audio loudness (loudnorm).
+func Loudnorm(o LoudnormOptions) (fflow.AtomicFilter, error) {
+	if o.Integrated != 0 && (o.Integrated < -70 || o.Integrated > -5) {
+		return fflow.AtomicFilter{}, errorf("loudnorm", "integrated must be between -70 and -5 LUFS")
+	}
+	if o.TruePeak < -9 || o.TruePeak > 0 {
+		return fflow.AtomicFilter{}, errorf("loudnorm", "true peak must be between -9 and 0 dBTP")
+	}
+	if o.LRA != 0 && (o.LRA < 1 || o.LRA > 50) {
+		return fflow.AtomicFilter{}, errorf("loudnorm", "LRA must be between 1 and 50 LU")
+	}
+
+	var p params
+	if o.Integrated != 0 {
+		p.add("I", formatFloat(o.Integrated))
+	}
+	if o.TruePeak != 0 {
+		p.add("TP", formatFloat(o.TruePeak))
+	}
+	if o.LRA != 0 {
+		p.add("LRA", formatFloat(o.LRA))
+	}
+	return p.filter("loudnorm"), nil
+}
+
+// AresampleOptions configura o filtro aresample. Async > 0 corrige o sincronismo
+// esticando ou comprimindo o áudio em até Async amostras por segundo.
+//
+// AresampleOptions configures the aresample filter. Async > 0 fixes sync
+// by stretching or squeezing the audio by up to Async samples per second.
+type AresampleOptions struct {
+	SampleRate int
+	Async      int
+}
+
+// Aresample reamostra o áudio (aresample).
+//
+// Aresample resamples the audio (aresample).
+func Aresample(o AresampleOptions) (fflow.AtomicFilter, error) {
+	if o.SampleRate < 0 || o.Async < 0 {
+		return fflow.AtomicFilter{}, errorf("aresample", "sample rate and async must be >= 0")
+	}
+	if o.SampleRate == 0 && o.Async == 0 {
+		return fflow.AtomicFilter{}, errorf("aresample", "sample rate or async must be set")
+	}
+
+	var p params
+	if o.SampleRate > 0 {
+		p.add("osr", strconv.Itoa(o.SampleRate))
+	}
+	if o.Async > 0 {
+		p.add("async", strconv.Itoa(o.Async))
+	}
+	return p.filter("aresample"), nil
+}
+
+// ATempo altera a velocidade do áudio sem mudar o tom (atempo). Como cada atempo aceita
+// apenas fatores entre 0.5 e 2.0, fatores fora desse intervalo geram uma cadeia de filtros.
+// Exemplo: `.Add(filters.Must(filters.ATempo(3))...)`
+//
+// ATempo changes the audio speed without changing its pitch (atempo). Since each atempo only
+// accepts factors between 0.5 and 2.0, factors outside that range produce a chain of filters.
+// Example: `.Add(filters.Must(filters.ATempo(3))...)`
+func ATempo(factor float64) ([]fflow.AtomicFilter, error) {
+	if factor <= 0 {
+		return nil, errorf("atempo", "factor must be > 0")
+	}
+
+	var chain []fflow.AtomicFilter
+	for factor > 2 {
+		chain = append(chain, atempo(2))
+		factor /= 2
+	}
+	for factor < 0.5 {
+		chain = append(chain, atempo(0.5))
+		factor /= 0.5
+	}
+	return append(chain, atempo(factor)), nil
+}
+
+func atempo(factor float64) fflow.AtomicFilter {
+	var p params
+	p.add("tempo", formatFloat(factor))
+	return p.filter("atempo")
+}
+
+// AmixOptions configura o filtro amix. Duration aceita "longest", "shortest" ou "first".
+//
+// AmixOptions configures the amix filter. Duration accepts "longest", "shortest" or "first".
+type AmixOptions struct {
+	Inputs            int
+	Duration          string
+	DropoutTransition time.Duration
+}
+
+// Amix mistura vários streams de áudio em um só (amix).
+//
+// Amix mixes several audio streams into one (amix).
+func Amix(o AmixOptions) (fflow.AtomicFilter, error) {
+	if o.Inputs < 2 {
+		return fflow.AtomicFilter{}, errorf("amix", "inputs must be >= 2")
+	}
+	if !oneOf(o.Duration, "", "longest", "shortest", "first") {
+		return fflow.AtomicFilter{}, errorf("amix", "invalid duration %q", o.Duration)
+	}
+	if o.DropoutTransition < 0 {
+		return fflow.AtomicFilter{}, errorf("amix", "dropout transition must be >= 0")
+	}
+
+	var p params
+	p.add("inputs", strconv.Itoa(o.Inputs))
+	if o.Duration != "" {
+		p.add("duration", o.Duration)
+	}
+	if o.DropoutTransition > 0 {
+		p.add("dropout_transition", formatFloat(o.DropoutTransition.Seconds()))
+	}
+	return p.filter("amix"), nil
+}
+
+// Amerge junta os canais de vários streams de áudio em um único stream (amerge).
+//
+// Amerge merges the channels of several audio streams into a single stream (amerge).
+func Amerge(inputs int) (fflow.AtomicFilter, error) {
+	if inputs < 1 || inputs > 64 {
+		return fflow.AtomicFilter{}, errorf("amerge", "inputs must be between 1 and 64")
+	}
+
+	var p params
+	p.add("inputs", strconv.Itoa(inputs))
+	return p.filter("amerge"), nil
+}
+
+// Pan remapeia os canais de áudio (pan).
+// Exemplo: `Pan("stereo", "c0=c0", "c1=c0")` duplica o canal esquerdo.
+//
+// Pan remaps the audio channels (pan).
+// Example: `Pan("stereo", "c0=c0", "c1=c0")` duplicates the left channel.
+func Pan(layout string, channels ...string) (fflow.AtomicFilter, error) {
+	if layout == "" || len(channels) == 0 {
+		return fflow.AtomicFilter{}, errorf("pan", "layout and at least one channel definition are required")
+	}
+
+	return fflow.AtomicFilter{
+		Name:   "pan",
+		Params: []string{escape(strings.Join(append([]string{layout}, channels...), "|"))},
+	}, nil
+}
+
+// ChannelSplit separa cada canal em um stream próprio (channelsplit).
+// Sem channels, todos os canais do layout são separados.
+//
+// ChannelSplit splits each channel into its own stream (channelsplit).
+// Without channels, every channel of the layout is split.
+func ChannelSplit(layout string, channels ...string) (fflow.AtomicFilter, error) {
+	if layout == "" {
+		return fflow.AtomicFilter{}, errorf("channelsplit", "channel layout is required")
+	}
+
+	var p params
+	p.add("channel_layout", layout)
+	if len(channels) > 0 {
+		p.add("channels", strings.Join(channels, "|"))
+	}
+	return p.filter("channelsplit"), nil
+}
+
+// Highpass atenua frequências abaixo de freq, em Hz (highpass).
+//
+// Highpass attenuates frequencies below freq, in Hz (highpass).
+func Highpass(freq float64) (fflow.AtomicFilter, error) {
+	return pass("highpass", freq)
+}
+
+// Lowpass atenua frequências acima de freq, em Hz (lowpass).
+//
+// Lowpass attenuates frequencies above freq, in Hz (lowpass).
+func Lowpass(freq float64) (fflow.AtomicFilter, error) {
+	return pass("lowpass", freq)
+}
+
+func pass(name string, freq float64) (fflow.AtomicFilter, error) {
+	if freq <= 0 {
+		return fflow.AtomicFilter{}, errorf(name, "frequency must be > 0 Hz")
+	}
+
+	var p params
+	p.add("f", formatFloat(freq))
+	return p.filter(name), nil
+}
+
+// AfadeOptions configura o filtro afade. Type aceita "in" ou "out";
+// Curve aceita as curvas do ffmpeg, ex.: "tri", "qsin", "log".
+//
+// AfadeOptions configures the afade filter. Type accepts "in" or "out";
+// Curve accepts the ffmpeg curves, e.g. "tri", "qsin", "log".
+type AfadeOptions struct {
+	Type     string
+	Start    time.Duration
+	Duration time.Duration
+	Curve    string
+}
+
+// Afade aplica um fade de entrada ou saída no áudio (afade).
+//
+// Afade applies a fade-in or fade-out to the audio (afade).
+func Afade(o AfadeOptions) (fflow.AtomicFilter, error) {
+	if !oneOf(o.Type, "in", "out") {
+		return fflow.AtomicFilter{}, errorf("afade", "type must be \"in\" or \"out\"")
+	}
+	if o.Start < 0 || o.Duration <= 0 {
+		return fflow.AtomicFilter{}, errorf("afade", "start must be >= 0 and duration > 0")
+	}
+
+	var p params
+	p.add("t", o.Type)
+	p.add("st", duration(o.Start))
+	p.add("d", duration(o.Duration))
+	if o.Curve != "" {
+		p.add("curve", o.Curve)
+	}
+	return p.filter("afade"), nil
+}
+
+// SilenceRemoveOptions configura o filtro silenceremove. Os thresholds são em dB;
+// períodos zerados desativam a remoção no início ou no fim.
+//
+// SilenceRemoveOptions configures the silenceremove filter. Thresholds are in dB;
+// zero periods disable removal at the start or at the end.
+type SilenceRemoveOptions struct {
+	StartPeriods   int
+	StartDuration  time.Duration
+	StartThreshold Decibel
+	StopPeriods    int
+	StopDuration   time.Duration
+	StopThreshold  Decibel
+}
+
+// SilenceRemove remove trechos de silêncio do áudio (silenceremove).
+//
+// SilenceRemove removes silent sections from the audio (silenceremove).
+func SilenceRemove(o SilenceRemoveOptions) (fflow.AtomicFilter, error) {
+	if o.StartPeriods < 0 || o.StartPeriods > 9000 {
+		return fflow.AtomicFilter{}, errorf("silenceremove", "start periods must be between 0 and 9000")
+	}
+	if o.StopPeriods < -9000 || o.StopPeriods > 9000 {
+		return fflow.AtomicFilter{}, errorf("silenceremove", "stop periods must be between -9000 and 9000")
+	}
+	if o.StartPeriods == 0 && o.StopPeriods == 0 {
+		return fflow.AtomicFilter{}, errorf("silenceremove", "start or stop periods must be set")
+	}
+	if o.StartDuration < 0 || o.StopDuration < 0 {
+		return fflow.AtomicFilter{}, errorf("silenceremove", "durations must be >= 0")
+	}
+
+	var p params
+	if o.StartPeriods != 0 {
+		p.add("start_periods", strconv.Itoa(o.StartPeriods))
+		p.add("start_duration", duration(o.StartDuration))
+		p.add("start_threshold", o.StartThreshold.String())
+	}
+	if o.StopPeriods != 0 {
+		p.add("stop_periods", strconv.Itoa(o.StopPeriods))
+		p.add("stop_duration", duration(o.StopDuration))
+		p.add("stop_threshold", o.StopThreshold.String())
+	}
+	return p.filter("silenceremove"), nil
+}
+
+// Adelay atrasa cada canal do áudio pelo tempo informado (adelay).
+// Com um único atraso, ele é aplicado a todos os canais.
+//
+// Adelay delays each audio channel by the given time (adelay).
+// With a single delay, it is applied to every channel.
+func Adelay(delays ...time.Duration) (fflow.AtomicFilter, error) {
+	if len(delays) == 0 {
+		return fflow.AtomicFilter{}, errorf("adelay", "at least one delay is required")
+	}
+
+	ms := make([]string, len(delays))
+	for i, d := range delays {
+		if d < 0 {
+			return fflow.AtomicFilter{}, errorf("adelay", "delays must be >= 0")
+		}
+		ms[i] = formatFloat(float64(d) / float64(time.Millisecond))
+	}
+
+	var p params
+	p.add("delays", strings.Join(ms, "|"))
+	if len(delays) == 1 {
+		p.add("all", "1")
+	}
+	return p.filter("adelay"), nil
+}
+
+// DynaudnormOptions configura o filtro dynaudnorm. Campos zerados usam o padrão do ffmpeg.
+// FrameLen entre 10ms e 8s, GaussSize ímpar entre 3 e 301, Peak entre 0 e 1 e MaxGain entre 1 e 100.
+//
+// DynaudnormOptions configures the dynaudnorm filter. Zero fields use the ffmpeg default.
+// FrameLen between 10ms and 8s, odd GaussSize between 3 and 301, Peak between 0 and 1 and MaxGain between 1 and 100.
+type DynaudnormOptions struct {
+	FrameLen  time.Duration
+	GaussSize int
+	Peak      float64
+	MaxGain   float64
+}
+
+// Dynaudnorm normaliza dinamicamente o volume do áudio (dynaudnorm).
+//
+// Dynaudnorm dynamically normalizes the audio volume (dynaudnorm).
+func Dynaudnorm(o DynaudnormOptions) (fflow.AtomicFilter, error) {
+	if o.FrameLen != 0 && (o.FrameLen < 10*time.Millisecond || o.FrameLen > 8*time.Second) {
+		return fflow.AtomicFilter{}, errorf("dynaudnorm", "frame length must be between 10ms and 8s")
+	}
+	if o.GaussSize != 0 && (o.GaussSize < 3 || o.GaussSize > 301 || o.GaussSize%2 == 0) {
+		return fflow.AtomicFilter{}, errorf("dynaudnorm", "gauss size must be an odd number between 3 and 301")
+	}
+	if o.Peak < 0 || o.Peak > 1 {
+		return fflow.AtomicFilter{}, errorf("dynaudnorm", "peak must be between 0 and 1")
+	}
+	if o.MaxGain != 0 && (o.MaxGain < 1 || o.MaxGain > 100) {
+		return fflow.AtomicFilter{}, errorf("dynaudnorm", "max gain must be between 1 and 100")
+	}
+
+	var p params
+	if o.FrameLen > 0 {
+		p.add("f", strconv.FormatInt(o.FrameLen.Milliseconds(), 10))
+	}
+	if o.GaussSize > 0 {
+		p.add("g", strconv.Itoa(o.GaussSize))
+	}
+	if o.Peak > 0 {
+		p.add("p", formatFloat(o.Peak))
+	}
+	if o.MaxGain > 0 {
+		p.add("m", formatFloat(o.MaxGain))
+	}
+	return p.filter("dynaudnorm"), nil
+}
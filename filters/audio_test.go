@@ -0,0 +1,195 @@
+package filters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Marlliton/fflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudioFilters(t *testing.T) {
+	t.Run("Construtores válidos", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			build    func() (fflow.AtomicFilter, error)
+			expected string
+		}{
+			{
+				name:     "Volume",
+				build:    func() (fflow.AtomicFilter, error) { return Volume(0.5) },
+				expected: "volume=volume=0.5",
+			},
+			{
+				name:     "VolumeDB",
+				build:    func() (fflow.AtomicFilter, error) { return VolumeDB(-6) },
+				expected: "volume=volume=-6dB",
+			},
+			{
+				name: "Loudnorm",
+				build: func() (fflow.AtomicFilter, error) {
+					return Loudnorm(LoudnormOptions{Integrated: -16, TruePeak: -1.5, LRA: 11})
+				},
+				expected: "loudnorm=I=-16:TP=-1.5:LRA=11",
+			},
+			{
+				name:     "Aresample",
+				build:    func() (fflow.AtomicFilter, error) { return Aresample(AresampleOptions{SampleRate: 48000, Async: 1}) },
+				expected: "aresample=osr=48000:async=1",
+			},
+			{
+				name:     "Amix",
+				build:    func() (fflow.AtomicFilter, error) { return Amix(AmixOptions{Inputs: 2, Duration: "longest"}) },
+				expected: "amix=inputs=2:duration=longest",
+			},
+			{
+				name:     "Amerge",
+				build:    func() (fflow.AtomicFilter, error) { return Amerge(2) },
+				expected: "amerge=inputs=2",
+			},
+			{
+				name:     "Pan",
+				build:    func() (fflow.AtomicFilter, error) { return Pan("stereo", "c0=c0", "c1=c0") },
+				expected: "pan=stereo|c0=c0|c1=c0",
+			},
+			{
+				name:     "ChannelSplit",
+				build:    func() (fflow.AtomicFilter, error) { return ChannelSplit("5.1", "FL", "FR") },
+				expected: "channelsplit=channel_layout=5.1:channels=FL|FR",
+			},
+			{
+				name:     "Highpass",
+				build:    func() (fflow.AtomicFilter, error) { return Highpass(200) },
+				expected: "highpass=f=200",
+			},
+			{
+				name:     "Lowpass",
+				build:    func() (fflow.AtomicFilter, error) { return Lowpass(3000) },
+				expected: "lowpass=f=3000",
+			},
+			{
+				name: "Afade",
+				build: func() (fflow.AtomicFilter, error) {
+					return Afade(AfadeOptions{Type: "out", Start: 58 * time.Second, Duration: 2 * time.Second, Curve: "qsin"})
+				},
+				expected: `afade=t=out:st=00\\:00\\:58.000:d=00\\:00\\:02.000:curve=qsin`,
+			},
+			{
+				name: "SilenceRemove",
+				build: func() (fflow.AtomicFilter, error) {
+					return SilenceRemove(SilenceRemoveOptions{StartPeriods: 1, StartDuration: 500 * time.Millisecond, StartThreshold: -50})
+				},
+				expected: `silenceremove=start_periods=1:start_duration=00\\:00\\:00.500:start_threshold=-50dB`,
+			},
+			{
+				name:     "Adelay em todos os canais",
+				build:    func() (fflow.AtomicFilter, error) { return Adelay(1500 * time.Millisecond) },
+				expected: "adelay=delays=1500:all=1",
+			},
+			{
+				name:     "Adelay por canal",
+				build:    func() (fflow.AtomicFilter, error) { return Adelay(0, 250*time.Millisecond) },
+				expected: "adelay=delays=0|250",
+			},
+			{
+				name: "Dynaudnorm",
+				build: func() (fflow.AtomicFilter, error) {
+					return Dynaudnorm(DynaudnormOptions{FrameLen: 500 * time.Millisecond, GaussSize: 31, Peak: 0.95})
+				},
+				expected: "dynaudnorm=f=500:g=31:p=0.95",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				f, err := tt.build()
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, f.String())
+			})
+		}
+	})
+
+	t.Run("ATempo encadeia fatores fora de 0.5-2.0", func(t *testing.T) {
+		tests := []struct {
+			factor   float64
+			expected []string
+		}{
+			{1.5, []string{"atempo=tempo=1.5"}},
+			{3, []string{"atempo=tempo=2", "atempo=tempo=1.5"}},
+			{0.25, []string{"atempo=tempo=0.5", "atempo=tempo=0.5"}},
+		}
+
+		for _, tt := range tests {
+			chain, err := ATempo(tt.factor)
+			require.NoError(t, err)
+
+			var got []string
+			for _, f := range chain {
+				got = append(got, f.String())
+			}
+			assert.Equal(t, tt.expected, got)
+		}
+
+		_, err := ATempo(0)
+		assert.Error(t, err)
+	})
+
+	t.Run("Validação de opções", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			build func() (fflow.AtomicFilter, error)
+		}{
+			{"Volume negativo", func() (fflow.AtomicFilter, error) { return Volume(-1) }},
+			{"Loudnorm fora do intervalo", func() (fflow.AtomicFilter, error) { return Loudnorm(LoudnormOptions{Integrated: -80}) }},
+			{"Loudnorm com true peak positivo", func() (fflow.AtomicFilter, error) { return Loudnorm(LoudnormOptions{TruePeak: 1}) }},
+			{"Aresample vazio", func() (fflow.AtomicFilter, error) { return Aresample(AresampleOptions{}) }},
+			{"Amix com um input", func() (fflow.AtomicFilter, error) { return Amix(AmixOptions{Inputs: 1}) }},
+			{"Amix com duração inválida", func() (fflow.AtomicFilter, error) { return Amix(AmixOptions{Inputs: 2, Duration: "max"}) }},
+			{"Amerge sem inputs", func() (fflow.AtomicFilter, error) { return Amerge(0) }},
+			{"Pan sem canais", func() (fflow.AtomicFilter, error) { return Pan("stereo") }},
+			{"ChannelSplit sem layout", func() (fflow.AtomicFilter, error) { return ChannelSplit("") }},
+			{"Highpass sem frequência", func() (fflow.AtomicFilter, error) { return Highpass(0) }},
+			{"Afade sem duração", func() (fflow.AtomicFilter, error) { return Afade(AfadeOptions{Type: "in"}) }},
+			{"SilenceRemove sem períodos", func() (fflow.AtomicFilter, error) { return SilenceRemove(SilenceRemoveOptions{}) }},
+			{"Adelay negativo", func() (fflow.AtomicFilter, error) { return Adelay(-time.Second) }},
+			{"Dynaudnorm com gauss par", func() (fflow.AtomicFilter, error) { return Dynaudnorm(DynaudnormOptions{GaussSize: 30}) }},
+			{"Dynaudnorm com frame longo", func() (fflow.AtomicFilter, error) { return Dynaudnorm(DynaudnormOptions{FrameLen: 10 * time.Second}) }},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tt.build()
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("Integração com o builder", func(t *testing.T) {
+		cmd := fflow.New().
+			Input("in.mp4").
+			Filter().
+			Simple(fflow.FilterAudio).
+			Add(Must(ATempo(3))...).
+			Add(Must(Loudnorm(LoudnormOptions{Integrated: -16}))).
+			Done().
+			Output("out.mp4").
+			Build()
+
+		assert.Equal(t, "ffmpeg -loglevel error -y -i in.mp4 -af atempo=tempo=2,atempo=tempo=1.5,loudnorm=I=-16 out.mp4", cmd)
+
+		cmd = fflow.New().
+			Input("voice.wav").
+			Input("music.mp3").
+			Filter().
+			Complex().
+			Chain([]string{"0:a", "1:a"}, []fflow.AtomicFilter{Must(Amix(AmixOptions{Inputs: 2, Duration: "first"}))}, []string{"a"}).
+			Done().
+			Map("[a]").
+			Output("mix.m4a").
+			Build()
+
+		assert.Equal(t, "ffmpeg -loglevel error -y -i voice.wav -i music.mp3 "+
+			"-filter_complex [0:a][1:a]amix=inputs=2:duration=first[a] -map [a] mix.m4a", cmd)
+	})
+}
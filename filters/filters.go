@@ -0,0 +1,83 @@
+// Package filters fornece construtores tipados para filtros comuns do FFmpeg.
+// Cada construtor valida suas opções e retorna um fflow.AtomicFilter, que pode ser
+// usado em simpleFilter.Add e complexFilter.Chain.
+//
+// Package filters provides typed constructors for common FFmpeg filters.
+// Each constructor validates its options and returns an fflow.AtomicFilter, which can be
+// used with simpleFilter.Add and complexFilter.Chain.
+package filters
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Marlliton/fflow"
+)
+
+// Must retorna o filtro (ou filtros, como em ATempo) ou entra em pânico se err não for nil.
+// Útil para filtros com opções constantes.
+// Exemplo: `.Add(filters.Must(filters.Scale(filters.ScaleOptions{Width: 1280, Height: -2})))`
+//
+// Must returns the filter (or filters, as in ATempo) or panics if err is not nil.
+// Useful for filters with constant options.
+// Example: `.Add(filters.Must(filters.Scale(filters.ScaleOptions{Width: 1280, Height: -2})))`
+func Must[T any](f T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// params acumula parâmetros nomeados (chave=valor) na ordem em que são adicionados.
+//
+// params accumulates named (key=value) parameters in the order they are added.
+type params []string
+
+func (p *params) add(key, value string) {
+	*p = append(*p, key+"="+value)
+}
+
+func (p params) filter(name string) fflow.AtomicFilter {
+	return fflow.AtomicFilter{Name: name, Params: p}
+}
+
+// Decibel representa um valor em dB, formatado com o sufixo "dB".
+//
+// Decibel represents a value in dB, formatted with the "dB" suffix.
+type Decibel float64
+
+func (d Decibel) String() string {
+	return formatFloat(float64(d)) + "dB"
+}
+
+func errorf(filter, format string, args ...any) error {
+	return fmt.Errorf("filters: %s: %s", filter, fmt.Sprintf(format, args...))
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// duration formata d com fflow.FormatDuration, escapado para uso dentro de um filtro.
+//
+// duration formats d with fflow.FormatDuration, escaped for use inside a filter.
+func duration(d time.Duration) string {
+	return escape(fflow.FormatDuration(d))
+}
+
+// escape escapa um valor de opção; o escape do filtergraph fica a cargo de AtomicFilter.String.
+//
+// escape escapes an option value; filtergraph escaping is left to AtomicFilter.String.
+func escape(value string) string {
+	return fflow.EscapeValue(value)
+}
@@ -0,0 +1,427 @@
+package filters
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Marlliton/fflow"
+)
+
+// ScaleOptions configura o filtro scale. Valores negativos em Width ou Height
+// preservam a proporção (-1) ou a preservam arredondando para um múltiplo de n (-n).
+//
+// ScaleOptions configures the scale filter. Negative Width or Height values
+// keep the aspect ratio (-1) or keep it rounding to a multiple of n (-n).
+type ScaleOptions struct {
+	Width  int
+	Height int
+
+	// Flags define o algoritmo de escala, ex.: "bicubic", "lanczos".
+	//
+	// Flags sets the scaling algorithm, e.g. "bicubic", "lanczos".
+	Flags string
+
+	// ForceOriginalAspectRatio aceita "disable", "decrease" ou "increase".
+	//
+	// ForceOriginalAspectRatio accepts "disable", "decrease" or "increase".
+	ForceOriginalAspectRatio string
+}
+
+// Scale redimensiona o vídeo (scale).
+//
+// Scale resizes the video (scale).
+func Scale(o ScaleOptions) (fflow.AtomicFilter, error) {
+	if o.Width == 0 && o.Height == 0 {
+		return fflow.AtomicFilter{}, errorf("scale", "width or height must be set")
+	}
+	if !oneOf(o.ForceOriginalAspectRatio, "", "disable", "decrease", "increase") {
+		return fflow.AtomicFilter{}, errorf("scale", "invalid force_original_aspect_ratio %q", o.ForceOriginalAspectRatio)
+	}
+
+	var p params
+	p.add("w", strconv.Itoa(o.Width))
+	p.add("h", strconv.Itoa(o.Height))
+	if o.Flags != "" {
+		p.add("flags", o.Flags)
+	}
+	if o.ForceOriginalAspectRatio != "" {
+		p.add("force_original_aspect_ratio", o.ForceOriginalAspectRatio)
+	}
+	return p.filter("scale"), nil
+}
+
+// CropOptions configura o filtro crop. Com Center, X e Y são ignorados e o recorte é centralizado.
+//
+// CropOptions configures the crop filter. With Center, X and Y are ignored and the crop is centered.
+type CropOptions struct {
+	Width  int
+	Height int
+	X      int
+	Y      int
+	Center bool
+}
+
+// Crop recorta uma área do vídeo (crop).
+//
+// Crop crops an area of the video (crop).
+func Crop(o CropOptions) (fflow.AtomicFilter, error) {
+	if o.Width <= 0 || o.Height <= 0 {
+		return fflow.AtomicFilter{}, errorf("crop", "width and height must be > 0")
+	}
+	if o.X < 0 || o.Y < 0 {
+		return fflow.AtomicFilter{}, errorf("crop", "x and y must be >= 0")
+	}
+
+	var p params
+	p.add("w", strconv.Itoa(o.Width))
+	p.add("h", strconv.Itoa(o.Height))
+	if !o.Center {
+		p.add("x", strconv.Itoa(o.X))
+		p.add("y", strconv.Itoa(o.Y))
+	}
+	return p.filter("crop"), nil
+}
+
+// PadOptions configura o filtro pad. X ou Y iguais a -1 centralizam o vídeo.
+//
+// PadOptions configures the pad filter. X or Y equal to -1 center the video.
+type PadOptions struct {
+	Width  int
+	Height int
+	X      int
+	Y      int
+	Color  string
+}
+
+// Pad adiciona bordas ao vídeo (pad).
+//
+// Pad adds borders to the video (pad).
+func Pad(o PadOptions) (fflow.AtomicFilter, error) {
+	if o.Width <= 0 || o.Height <= 0 {
+		return fflow.AtomicFilter{}, errorf("pad", "width and height must be > 0")
+	}
+	if o.X < -1 || o.Y < -1 {
+		return fflow.AtomicFilter{}, errorf("pad", "x and y must be >= -1")
+	}
+
+	var p params
+	p.add("w", strconv.Itoa(o.Width))
+	p.add("h", strconv.Itoa(o.Height))
+	p.add("x", strconv.Itoa(o.X))
+	p.add("y", strconv.Itoa(o.Y))
+	if o.Color != "" {
+		p.add("color", o.Color)
+	}
+	return p.filter("pad"), nil
+}
+
+// FPS converte o vídeo para uma taxa de quadros constante (fps).
+//
+// FPS converts the video to a constant frame rate (fps).
+func FPS(rate float64) (fflow.AtomicFilter, error) {
+	if rate <= 0 {
+		return fflow.AtomicFilter{}, errorf("fps", "rate must be > 0")
+	}
+
+	var p params
+	p.add("fps", formatFloat(rate))
+	return p.filter("fps"), nil
+}
+
+// Format converte o vídeo para um dos formatos de pixel informados (format).
+//
+// Format converts the video to one of the given pixel formats (format).
+func Format(pixFmts ...string) (fflow.AtomicFilter, error) {
+	if len(pixFmts) == 0 {
+		return fflow.AtomicFilter{}, errorf("format", "at least one pixel format is required")
+	}
+
+	var p params
+	p.add("pix_fmts", strings.Join(pixFmts, "|"))
+	return p.filter("format"), nil
+}
+
+// SetSAR define a proporção de amostra (setsar), ex.: SetSAR(1, 1).
+//
+// SetSAR sets the sample aspect ratio (setsar), e.g. SetSAR(1, 1).
+func SetSAR(num, den int) (fflow.AtomicFilter, error) {
+	if num < 0 || den <= 0 {
+		return fflow.AtomicFilter{}, errorf("setsar", "invalid ratio %d/%d", num, den)
+	}
+
+	var p params
+	p.add("r", strconv.Itoa(num)+"/"+strconv.Itoa(den))
+	return p.filter("setsar"), nil
+}
+
+// TransposeDir é a direção do filtro transpose.
+//
+// TransposeDir is the direction of the transpose filter.
+type TransposeDir string
+
+const (
+	TransposeCClockFlip TransposeDir = "cclock_flip"
+	TransposeClock      TransposeDir = "clock"
+	TransposeCClock     TransposeDir = "cclock"
+	TransposeClockFlip  TransposeDir = "clock_flip"
+)
+
+// Transpose rotaciona o vídeo em 90 graus (transpose).
+//
+// Transpose rotates the video by 90 degrees (transpose).
+func Transpose(dir TransposeDir) (fflow.AtomicFilter, error) {
+	if !oneOf(string(dir), string(TransposeCClockFlip), string(TransposeClock), string(TransposeCClock), string(TransposeClockFlip)) {
+		return fflow.AtomicFilter{}, errorf("transpose", "invalid direction %q", dir)
+	}
+
+	var p params
+	p.add("dir", string(dir))
+	return p.filter("transpose"), nil
+}
+
+// RotateOptions configura o filtro rotate. Degrees é convertido para radianos pelo ffmpeg.
+//
+// RotateOptions configures the rotate filter. Degrees is converted to radians by ffmpeg.
+type RotateOptions struct {
+	Degrees   float64
+	OutWidth  string
+	OutHeight string
+	FillColor string
+}
+
+// Rotate rotaciona o vídeo por um ângulo arbitrário (rotate).
+//
+// Rotate rotates the video by an arbitrary angle (rotate).
+func Rotate(o RotateOptions) (fflow.AtomicFilter, error) {
+	if o.Degrees < -360 || o.Degrees > 360 {
+		return fflow.AtomicFilter{}, errorf("rotate", "degrees must be between -360 and 360")
+	}
+
+	var p params
+	p.add("a", formatFloat(o.Degrees)+"*PI/180")
+	if o.OutWidth != "" {
+		p.add("ow", escape(o.OutWidth))
+	}
+	if o.OutHeight != "" {
+		p.add("oh", escape(o.OutHeight))
+	}
+	if o.FillColor != "" {
+		p.add("c", o.FillColor)
+	}
+	return p.filter("rotate"), nil
+}
+
+// OverlayOptions configura o filtro overlay. X e Y aceitam expressões, ex.: "W-w-10".
+//
+// OverlayOptions configures the overlay filter. X and Y accept expressions, e.g. "W-w-10".
+type OverlayOptions struct {
+	X string
+	Y string
+
+	// EOFAction aceita "repeat", "endall" ou "pass".
+	//
+	// EOFAction accepts "repeat", "endall" or "pass".
+	EOFAction string
+	Shortest  bool
+}
+
+// Overlay sobrepõe o segundo input sobre o primeiro (overlay).
+//
+// Overlay overlays the second input on top of the first (overlay).
+func Overlay(o OverlayOptions) (fflow.AtomicFilter, error) {
+	if !oneOf(o.EOFAction, "", "repeat", "endall", "pass") {
+		return fflow.AtomicFilter{}, errorf("overlay", "invalid eof_action %q", o.EOFAction)
+	}
+
+	var p params
+	if o.X != "" {
+		p.add("x", escape(o.X))
+	}
+	if o.Y != "" {
+		p.add("y", escape(o.Y))
+	}
+	if o.EOFAction != "" {
+		p.add("eof_action", o.EOFAction)
+	}
+	if o.Shortest {
+		p.add("shortest", "1")
+	}
+	return p.filter("overlay"), nil
+}
+
+// DrawTextOptions configura o filtro drawtext. Text é escapado automaticamente.
+//
+// DrawTextOptions configures the drawtext filter. Text is escaped automatically.
+type DrawTextOptions struct {
+	Text      string
+	FontFile  string
+	FontSize  int
+	FontColor string
+	X         string
+	Y         string
+	Box       bool
+	BoxColor  string
+}
+
+// DrawText escreve um texto sobre o vídeo (drawtext).
+//
+// DrawText draws text on top of the video (drawtext).
+func DrawText(o DrawTextOptions) (fflow.AtomicFilter, error) {
+	if o.Text == "" {
+		return fflow.AtomicFilter{}, errorf("drawtext", "text is required")
+	}
+	if o.FontSize < 0 {
+		return fflow.AtomicFilter{}, errorf("drawtext", "fontsize must be >= 0")
+	}
+
+	var p params
+	p.add("text", escape(o.Text))
+	if o.FontFile != "" {
+		p.add("fontfile", escape(o.FontFile))
+	}
+	if o.FontSize > 0 {
+		p.add("fontsize", strconv.Itoa(o.FontSize))
+	}
+	if o.FontColor != "" {
+		p.add("fontcolor", o.FontColor)
+	}
+	if o.X != "" {
+		p.add("x", escape(o.X))
+	}
+	if o.Y != "" {
+		p.add("y", escape(o.Y))
+	}
+	if o.Box {
+		p.add("box", "1")
+	}
+	if o.BoxColor != "" {
+		p.add("boxcolor", o.BoxColor)
+	}
+	return p.filter("drawtext"), nil
+}
+
+// FadeOptions configura o filtro fade. Type aceita "in" ou "out".
+//
+// FadeOptions configures the fade filter. Type accepts "in" or "out".
+type FadeOptions struct {
+	Type     string
+	Start    time.Duration
+	Duration time.Duration
+	Color    string
+}
+
+// Fade aplica um fade de entrada ou saída no vídeo (fade).
+//
+// Fade applies a fade-in or fade-out to the video (fade).
+func Fade(o FadeOptions) (fflow.AtomicFilter, error) {
+	if !oneOf(o.Type, "in", "out") {
+		return fflow.AtomicFilter{}, errorf("fade", "type must be \"in\" or \"out\"")
+	}
+	if o.Start < 0 || o.Duration <= 0 {
+		return fflow.AtomicFilter{}, errorf("fade", "start must be >= 0 and duration > 0")
+	}
+
+	var p params
+	p.add("t", o.Type)
+	p.add("st", duration(o.Start))
+	p.add("d", duration(o.Duration))
+	if o.Color != "" {
+		p.add("c", o.Color)
+	}
+	return p.filter("fade"), nil
+}
+
+// TrimOptions configura o filtro trim. Campos zerados são omitidos.
+//
+// TrimOptions configures the trim filter. Zero fields are omitted.
+type TrimOptions struct {
+	Start    time.Duration
+	End      time.Duration
+	Duration time.Duration
+}
+
+// Trim mantém apenas um trecho do vídeo (trim). Normalmente seguido de SetPTS(ResetPTS).
+//
+// Trim keeps only a section of the video (trim). Usually followed by SetPTS(ResetPTS).
+func Trim(o TrimOptions) (fflow.AtomicFilter, error) {
+	if o.Start < 0 || o.End < 0 || o.Duration < 0 {
+		return fflow.AtomicFilter{}, errorf("trim", "times must be >= 0")
+	}
+	if o.End > 0 && o.End <= o.Start {
+		return fflow.AtomicFilter{}, errorf("trim", "end must be after start")
+	}
+	if o.Start == 0 && o.End == 0 && o.Duration == 0 {
+		return fflow.AtomicFilter{}, errorf("trim", "start, end or duration must be set")
+	}
+
+	var p params
+	if o.Start > 0 {
+		p.add("start", duration(o.Start))
+	}
+	if o.End > 0 {
+		p.add("end", duration(o.End))
+	}
+	if o.Duration > 0 {
+		p.add("duration", duration(o.Duration))
+	}
+	return p.filter("trim"), nil
+}
+
+// ResetPTS reinicia os timestamps a partir de zero; usado após Trim.
+//
+// ResetPTS restarts timestamps from zero; used after Trim.
+const ResetPTS = "PTS-STARTPTS"
+
+// SetPTS altera os timestamps dos frames de vídeo (setpts), ex.: "0.5*PTS".
+//
+// SetPTS changes the video frame timestamps (setpts), e.g. "0.5*PTS".
+func SetPTS(expr string) (fflow.AtomicFilter, error) {
+	if expr == "" {
+		return fflow.AtomicFilter{}, errorf("setpts", "expression is required")
+	}
+
+	var p params
+	p.add("expr", escape(expr))
+	return p.filter("setpts"), nil
+}
+
+// YadifOptions configura o filtro yadif.
+// Mode aceita "send_frame", "send_field", "send_frame_nospatial" ou "send_field_nospatial";
+// Parity aceita "tff", "bff" ou "auto"; Deint aceita "all" ou "interlaced".
+//
+// YadifOptions configures the yadif filter.
+// Mode accepts "send_frame", "send_field", "send_frame_nospatial" or "send_field_nospatial";
+// Parity accepts "tff", "bff" or "auto"; Deint accepts "all" or "interlaced".
+type YadifOptions struct {
+	Mode   string
+	Parity string
+	Deint  string
+}
+
+// Yadif remove o entrelaçamento do vídeo (yadif).
+//
+// Yadif deinterlaces the video (yadif).
+func Yadif(o YadifOptions) (fflow.AtomicFilter, error) {
+	if !oneOf(o.Mode, "", "send_frame", "send_field", "send_frame_nospatial", "send_field_nospatial") {
+		return fflow.AtomicFilter{}, errorf("yadif", "invalid mode %q", o.Mode)
+	}
+	if !oneOf(o.Parity, "", "tff", "bff", "auto") {
+		return fflow.AtomicFilter{}, errorf("yadif", "invalid parity %q", o.Parity)
+	}
+	if !oneOf(o.Deint, "", "all", "interlaced") {
+		return fflow.AtomicFilter{}, errorf("yadif", "invalid deint %q", o.Deint)
+	}
+
+	var p params
+	if o.Mode != "" {
+		p.add("mode", o.Mode)
+	}
+	if o.Parity != "" {
+		p.add("parity", o.Parity)
+	}
+	if o.Deint != "" {
+		p.add("deint", o.Deint)
+	}
+	return p.filter("yadif"), nil
+}
@@ -0,0 +1,201 @@
+package filters
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Marlliton/fflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain fixa o binário padrão para que FFMPEG_BIN não altere os comandos esperados.
+func TestMain(m *testing.M) {
+	fflow.DefaultBinary = "ffmpeg"
+	os.Exit(m.Run())
+}
+
+func TestVideoFilters(t *testing.T) {
+	t.Run("Construtores válidos", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			build    func() (fflow.AtomicFilter, error)
+			expected string
+		}{
+			{
+				name: "Scale",
+				build: func() (fflow.AtomicFilter, error) {
+					return Scale(ScaleOptions{Width: 1280, Height: -2, Flags: "lanczos"})
+				},
+				expected: "scale=w=1280:h=-2:flags=lanczos",
+			},
+			{
+				name: "Scale com aspect ratio",
+				build: func() (fflow.AtomicFilter, error) {
+					return Scale(ScaleOptions{Width: 1920, Height: 1080, ForceOriginalAspectRatio: "decrease"})
+				},
+				expected: "scale=w=1920:h=1080:force_original_aspect_ratio=decrease",
+			},
+			{
+				name:     "Crop",
+				build:    func() (fflow.AtomicFilter, error) { return Crop(CropOptions{Width: 640, Height: 360, X: 10, Y: 20}) },
+				expected: "crop=w=640:h=360:x=10:y=20",
+			},
+			{
+				name:     "Crop centralizado",
+				build:    func() (fflow.AtomicFilter, error) { return Crop(CropOptions{Width: 640, Height: 360, Center: true}) },
+				expected: "crop=w=640:h=360",
+			},
+			{
+				name: "Pad",
+				build: func() (fflow.AtomicFilter, error) {
+					return Pad(PadOptions{Width: 1920, Height: 1080, X: -1, Y: -1, Color: "black"})
+				},
+				expected: "pad=w=1920:h=1080:x=-1:y=-1:color=black",
+			},
+			{
+				name:     "FPS",
+				build:    func() (fflow.AtomicFilter, error) { return FPS(29.97) },
+				expected: "fps=fps=29.97",
+			},
+			{
+				name:     "Format",
+				build:    func() (fflow.AtomicFilter, error) { return Format("yuv420p", "nv12") },
+				expected: "format=pix_fmts=yuv420p|nv12",
+			},
+			{
+				name:     "SetSAR",
+				build:    func() (fflow.AtomicFilter, error) { return SetSAR(1, 1) },
+				expected: "setsar=r=1/1",
+			},
+			{
+				name:     "Transpose",
+				build:    func() (fflow.AtomicFilter, error) { return Transpose(TransposeClock) },
+				expected: "transpose=dir=clock",
+			},
+			{
+				name:     "Rotate",
+				build:    func() (fflow.AtomicFilter, error) { return Rotate(RotateOptions{Degrees: 45, FillColor: "none"}) },
+				expected: "rotate=a=45*PI/180:c=none",
+			},
+			{
+				name: "Overlay",
+				build: func() (fflow.AtomicFilter, error) {
+					return Overlay(OverlayOptions{X: "W-w-10", Y: "10", Shortest: true})
+				},
+				expected: "overlay=x=W-w-10:y=10:shortest=1",
+			},
+			{
+				name: "DrawText escapa o texto",
+				build: func() (fflow.AtomicFilter, error) {
+					return DrawText(DrawTextOptions{Text: "Time: 10, ok", FontSize: 42, FontColor: "white"})
+				},
+				expected: `drawtext=text=Time\\: 10\, ok:fontsize=42:fontcolor=white`,
+			},
+			{
+				name: "Fade",
+				build: func() (fflow.AtomicFilter, error) {
+					return Fade(FadeOptions{Type: "in", Duration: 1500 * time.Millisecond})
+				},
+				expected: `fade=t=in:st=00\\:00\\:00.000:d=00\\:00\\:01.500`,
+			},
+			{
+				name: "Trim",
+				build: func() (fflow.AtomicFilter, error) {
+					return Trim(TrimOptions{Start: 5 * time.Second, End: 10 * time.Second})
+				},
+				expected: `trim=start=00\\:00\\:05.000:end=00\\:00\\:10.000`,
+			},
+			{
+				name:     "SetPTS",
+				build:    func() (fflow.AtomicFilter, error) { return SetPTS(ResetPTS) },
+				expected: "setpts=expr=PTS-STARTPTS",
+			},
+			{
+				name:     "Yadif",
+				build:    func() (fflow.AtomicFilter, error) { return Yadif(YadifOptions{Mode: "send_field", Parity: "auto"}) },
+				expected: "yadif=mode=send_field:parity=auto",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				f, err := tt.build()
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, f.String())
+			})
+		}
+	})
+
+	t.Run("Validação de opções", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			build func() (fflow.AtomicFilter, error)
+		}{
+			{"Scale sem dimensões", func() (fflow.AtomicFilter, error) { return Scale(ScaleOptions{}) }},
+			{"Scale com aspect ratio inválido", func() (fflow.AtomicFilter, error) {
+				return Scale(ScaleOptions{Width: 10, ForceOriginalAspectRatio: "keep"})
+			}},
+			{"Crop com tamanho zero", func() (fflow.AtomicFilter, error) { return Crop(CropOptions{Width: 0, Height: 10}) }},
+			{"Crop com posição negativa", func() (fflow.AtomicFilter, error) { return Crop(CropOptions{Width: 10, Height: 10, X: -5}) }},
+			{"Pad com posição inválida", func() (fflow.AtomicFilter, error) { return Pad(PadOptions{Width: 10, Height: 10, X: -2}) }},
+			{"FPS zero", func() (fflow.AtomicFilter, error) { return FPS(0) }},
+			{"Format vazio", func() (fflow.AtomicFilter, error) { return Format() }},
+			{"SetSAR com denominador zero", func() (fflow.AtomicFilter, error) { return SetSAR(1, 0) }},
+			{"Transpose inválido", func() (fflow.AtomicFilter, error) { return Transpose("left") }},
+			{"Rotate fora do intervalo", func() (fflow.AtomicFilter, error) { return Rotate(RotateOptions{Degrees: 720}) }},
+			{"Overlay com eof_action inválido", func() (fflow.AtomicFilter, error) { return Overlay(OverlayOptions{EOFAction: "stop"}) }},
+			{"DrawText sem texto", func() (fflow.AtomicFilter, error) { return DrawText(DrawTextOptions{}) }},
+			{"Fade com tipo inválido", func() (fflow.AtomicFilter, error) { return Fade(FadeOptions{Type: "up", Duration: time.Second}) }},
+			{"Fade sem duração", func() (fflow.AtomicFilter, error) { return Fade(FadeOptions{Type: "in"}) }},
+			{"Trim vazio", func() (fflow.AtomicFilter, error) { return Trim(TrimOptions{}) }},
+			{"Trim com fim antes do início", func() (fflow.AtomicFilter, error) {
+				return Trim(TrimOptions{Start: 10 * time.Second, End: 5 * time.Second})
+			}},
+			{"SetPTS vazio", func() (fflow.AtomicFilter, error) { return SetPTS("") }},
+			{"Yadif com paridade inválida", func() (fflow.AtomicFilter, error) { return Yadif(YadifOptions{Parity: "top"}) }},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := tt.build()
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("Must entra em pânico com erro", func(t *testing.T) {
+		assert.Panics(t, func() { Must(FPS(-1)) })
+		assert.NotPanics(t, func() { Must(FPS(30)) })
+	})
+
+	t.Run("Integração com o builder", func(t *testing.T) {
+		cmd := fflow.New().
+			Input("in.mp4").
+			Input("logo.png").
+			Filter().
+			Complex().
+			Chain([]string{"0:v"}, []fflow.AtomicFilter{Must(Scale(ScaleOptions{Width: 1280, Height: -2}))}, []string{"main"}).
+			Chain([]string{"main", "1:v"}, []fflow.AtomicFilter{Must(Overlay(OverlayOptions{X: "W-w-10", Y: "10"}))}, []string{"out"}).
+			Done().
+			Map("[out]").
+			Output("out.mp4").
+			Build()
+
+		assert.Equal(t, "ffmpeg -loglevel error -y -i in.mp4 -i logo.png "+
+			"-filter_complex [0:v]scale=w=1280:h=-2[main];[main][1:v]overlay=x=W-w-10:y=10[out] -map [out] out.mp4", cmd)
+
+		cmd = fflow.New().
+			Input("in.mp4").
+			Filter().
+			Simple(fflow.FilterVideo).
+			Add(Must(Yadif(YadifOptions{}))).
+			Add(Must(Format("yuv420p"))).
+			Done().
+			Output("out.mp4").
+			Build()
+
+		assert.Equal(t, "ffmpeg -loglevel error -y -i in.mp4 -vf yadif,format=pix_fmts=yuv420p out.mp4", cmd)
+	})
+}
@@ -1,6 +1,9 @@
 package fflow
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 type beforeReadStage interface {
 	// Raw adiciona um argumento bruto ao comando FFmpeg, antes do -i
@@ -8,51 +11,91 @@ type beforeReadStage interface {
 	// Raw adds a raw argument to the FFmpeg command, before -i flag
 	Raw(value string) beforeReadStage
 
-	// Input adiciona um arquivo de entrada (-i) e transiciona para o ReadStage.
+	// Input adiciona um arquivo de entrada (-i) com suas opções e transiciona para o ReadStage.
 	//
-	// Input adds an input file (-i) and transitions to ReadStage.
-	Input(path string) readStagee
+	// Input adds an input file (-i) with its options and transitions to ReadStage.
+	Input(path string, opts ...InputOption) readStagee
 
-	// Ss adiciona a flag -ss antes do -i, realizando um seek rápido na entrada.
+	// InputReader adiciona um input lido de r através de um pipe (ver readStagee.InputReader).
 	//
-	// Ss adds the -ss flag before -i, performing a fast seek on the input.
+	// InputReader adds an input read from r through a pipe (see readStagee.InputReader).
+	InputReader(r io.Reader, format string, opts ...InputOption) readStagee
+
+	// Ss adiciona a flag -ss antes do primeiro -i, realizando um seek rápido na entrada.
+	// Equivale a InputSs no primeiro Input.
+	//
+	// Ss adds the -ss flag before the first -i, performing a fast seek on the input.
+	// Equivalent to InputSs on the first Input.
 	Ss(d time.Duration) beforeReadStage
 
-	// To adiciona a flag -to antes do -i, definindo o tempo final absoluto da leitura.
+	// To adiciona a flag -to antes do primeiro -i, definindo o tempo final absoluto da leitura.
 	//
-	// To adds the -to flag before -i, defining the absolute end time of the input read.
+	// To adds the -to flag before the first -i, defining the absolute end time of the input read.
 	To(d time.Duration) beforeReadStage
 
-	// T adiciona a flag -t antes do -i, limitando quanto da entrada será lida.
+	// T adiciona a flag -t antes do primeiro -i, limitando quanto da entrada será lida.
 	//
-	// T adds the -t flag before -i, limiting how much of the input is read.
+	// T adds the -t flag before the first -i, limiting how much of the input is read.
 	T(d time.Duration) beforeReadStage
+
+	// LogLevel define o valor de -loglevel ("quiet", "error", "warning", "info", "debug"...),
+	// substituindo o "error" usado por padrão em New.
+	//
+	// LogLevel sets the -loglevel value ("quiet", "error", "warning", "info", "debug"...),
+	// replacing the "error" used by default in New.
+	LogLevel(level string) beforeReadStage
+
+	// Clone retorna uma cópia independente deste estágio. Use-a para derivar comandos
+	// de um builder base; cada cópia pode ser usada em sua própria goroutine. A exceção
+	// são os readers e writers de InputReader e OutputWriter, que não podem ser copiados
+	// e continuam compartilhados: cópias que os usam não devem ser executadas ao mesmo tempo.
+	//
+	// Clone returns an independent copy of this stage. Use it to derive commands
+	// from a base builder; each copy can be used from its own goroutine. The exception
+	// is the readers and writers of InputReader and OutputWriter, which cannot be copied
+	// and remain shared: copies that use them must not be run at the same time.
+	Clone() beforeReadStage
 }
 
 type beforeReadCtx struct{ b *ffmpegBuilder }
 
-func (c *beforeReadCtx) Input(path string) readStagee {
+func (c *beforeReadCtx) Input(path string, opts ...InputOption) readStagee {
 	read := &readCtx{c.b}
-	read.Input(path)
+	read.Input(path, opts...)
+	return read
+}
+
+func (c *beforeReadCtx) InputReader(r io.Reader, format string, opts ...InputOption) readStagee {
+	read := &readCtx{c.b}
+	read.InputReader(r, format, opts...)
 	return read
 }
 
 func (c *beforeReadCtx) Raw(value string) beforeReadStage {
-	c.b.beforeRead = append(c.b.beforeRead, value)
+	c.b.global = append(c.b.global, value)
+	return c
+}
+
+func (c *beforeReadCtx) LogLevel(level string) beforeReadStage {
+	c.b.setLogLevel(level)
 	return c
 }
 
 func (c *beforeReadCtx) T(d time.Duration) beforeReadStage {
-	c.b.beforeRead = append(c.b.beforeRead, "-t", fmtDuration(d))
+	c.b.pending = append(c.b.pending, "-t", fmtDuration(d))
 	return c
 }
 
 func (c *beforeReadCtx) Ss(d time.Duration) beforeReadStage {
-	c.b.beforeRead = append(c.b.beforeRead, "-ss", fmtDuration(d))
+	c.b.pending = append(c.b.pending, "-ss", fmtDuration(d))
 	return c
 }
 
 func (c *beforeReadCtx) To(d time.Duration) beforeReadStage {
-	c.b.beforeRead = append(c.b.beforeRead, "-to", fmtDuration(d))
+	c.b.pending = append(c.b.pending, "-to", fmtDuration(d))
 	return c
 }
+
+func (c *beforeReadCtx) Clone() beforeReadStage {
+	return &beforeReadCtx{c.b.clone()}
+}
@@ -15,6 +15,11 @@ func TestGlobalStage(t *testing.T) {
 				builder:  New().Input(in).Output(out),
 				expected: "ffmpeg -loglevel error -y -i video.mp4 out.mp4",
 			},
+			{
+				name:     "LogLevel substitui o nível padrão",
+				builder:  New().LogLevel("warning").Input(in).Output(out),
+				expected: "ffmpeg -loglevel warning -y -i video.mp4 out.mp4",
+			},
 		})
 	})
 }
@@ -0,0 +1,310 @@
+package fflow
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Graph representa as cadeias de um -filter_complex e rastreia os pads
+// (labels) produzidos e consumidos por elas.
+//
+// Graph represents the chains of a -filter_complex and tracks the pads
+// (labels) they produce and consume.
+type Graph struct {
+	Chains []Chain
+}
+
+func (g Graph) String() string {
+	parts := make([]string, len(g.Chains))
+	for i, c := range g.Chains {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ";")
+}
+
+// Validate verifica as ligações do graph: referências a inputs inexistentes (ex.: "2:v"
+// com apenas dois inputs), labels consumidos sem serem produzidos, produzidos ou consumidos
+// mais de uma vez e labels que não são consumidos nem usados em -map. mapped contém os
+// valores passados para Map, ex.: "[out]".
+//
+// Validate checks the graph links: references to missing inputs (e.g. "2:v" with only
+// two inputs), labels consumed without being produced, produced or consumed more than once,
+// and labels that are neither consumed nor used by -map. mapped holds the values passed
+// to Map, e.g. "[out]".
+func (g Graph) Validate(inputs int, mapped ...string) error {
+	var errs []error
+
+	produced := map[string]int{}
+	consumed := map[string]int{}
+	var order []string
+
+	for _, c := range g.Chains {
+		for _, out := range c.Output {
+			if err := checkLabel(out); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if produced[out] == 0 {
+				order = append(order, out)
+			}
+			produced[out]++
+		}
+	}
+
+	for _, c := range g.Chains {
+		for _, in := range c.Inputs {
+			if index, ok := inputIndex(in); ok {
+				if index >= inputs {
+					errs = append(errs, fmt.Errorf("graph: pad [%s] references input %d, but only %d inputs were added", in, index, inputs))
+				}
+				continue
+			}
+			if err := checkLabel(in); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if produced[in] == 0 {
+				errs = append(errs, fmt.Errorf("graph: label [%s] is consumed but never produced", in))
+			}
+			consumed[in]++
+		}
+	}
+
+	for _, label := range order {
+		if produced[label] > 1 {
+			errs = append(errs, fmt.Errorf("graph: label [%s] is produced %d times", label, produced[label]))
+		}
+		if consumed[label] > 1 {
+			errs = append(errs, fmt.Errorf("graph: label [%s] is consumed %d times; use split or asplit", label, consumed[label]))
+		}
+	}
+
+	used := map[string]bool{}
+	for _, m := range mapped {
+		label, ok := strings.CutPrefix(m, "[")
+		if !ok {
+			continue
+		}
+		label = strings.TrimSuffix(label, "]")
+		used[label] = true
+		if produced[label] == 0 {
+			errs = append(errs, fmt.Errorf("graph: map [%s] references an unknown label", label))
+		}
+	}
+
+	for _, label := range order {
+		if consumed[label] == 0 && !used[label] {
+			errs = append(errs, fmt.Errorf("graph: label [%s] is never consumed or mapped", label))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// check confere uma nova cadeia contra as que já estão no graph e retorna o primeiro
+// erro de ligação: labels inválidos, inputs inexistentes e labels produzidos ou
+// consumidos mais de uma vez.
+//
+// check checks a new chain against the ones already in the graph and returns the first
+// wiring error: invalid labels, missing inputs and labels produced or consumed more
+// than once.
+func (g Graph) check(c Chain, inputs int) error {
+	produced := map[string]bool{}
+	consumed := map[string]bool{}
+	for _, prev := range g.Chains {
+		for _, out := range prev.Output {
+			produced[out] = true
+		}
+		for _, in := range prev.Inputs {
+			consumed[in] = true
+		}
+	}
+
+	for _, out := range c.Output {
+		if err := checkLabel(out); err != nil {
+			return err
+		}
+		if produced[out] {
+			return fmt.Errorf("graph: label [%s] is produced 2 times", out)
+		}
+		produced[out] = true
+	}
+
+	for _, in := range c.Inputs {
+		if index, ok := inputIndex(in); ok {
+			if index >= inputs {
+				return fmt.Errorf("graph: pad [%s] references input %d, but only %d inputs were added", in, index, inputs)
+			}
+			continue
+		}
+		if err := checkLabel(in); err != nil {
+			return err
+		}
+		if consumed[in] {
+			return fmt.Errorf("graph: label [%s] is consumed 2 times; use split or asplit", in)
+		}
+		consumed[in] = true
+	}
+	return nil
+}
+
+// unresolved retorna um erro para o primeiro label consumido que nenhuma cadeia produz.
+//
+// unresolved returns an error for the first consumed label that no chain produces.
+func (g Graph) unresolved() error {
+	produced := map[string]bool{}
+	for _, c := range g.Chains {
+		for _, out := range c.Output {
+			produced[out] = true
+		}
+	}
+	for _, c := range g.Chains {
+		for _, in := range c.Inputs {
+			if _, ok := inputIndex(in); ok || produced[in] {
+				continue
+			}
+			return fmt.Errorf("graph: label [%s] is consumed but never produced", in)
+		}
+	}
+	return nil
+}
+
+// inputIndex identifica referências a inputs, como "0", "1:v" ou "0:a:1".
+//
+// inputIndex identifies input references, such as "0", "1:v" or "0:a:1".
+func inputIndex(label string) (int, bool) {
+	head, _, _ := strings.Cut(label, ":")
+	index, err := strconv.Atoi(head)
+	if err != nil {
+		return 0, false
+	}
+	return index, true
+}
+
+func checkLabel(label string) error {
+	if label == "" || strings.ContainsAny(label, "[];, \t") {
+		return fmt.Errorf("graph: invalid label %q", label)
+	}
+	return nil
+}
+
+// graph retorna as cadeias complexas registradas no builder.
+//
+// graph returns the complex chains registered in the builder.
+func (b *ffmpegBuilder) graph() Graph {
+	var g Graph
+	for _, f := range b.filters {
+		if c, ok := f.(Chain); ok {
+			g.Chains = append(g.Chains, c)
+		}
+	}
+	return g
+}
+
+// rawGraph informa se algum filtergraph foi passado como argumento cru (Raw, Parse ou
+// -filter_complex_script), fora das cadeias que o builder conhece.
+//
+// rawGraph reports whether some filtergraph was passed as a raw argument (Raw, Parse or
+// -filter_complex_script), outside the chains the builder knows about.
+func (b *ffmpegBuilder) rawGraph() bool {
+	args := [][]string{b.global, b.pending}
+	for _, in := range b.inputs {
+		args = append(args, in.args)
+	}
+	for _, o := range b.outputs {
+		args = append(args, o.args)
+	}
+	for _, list := range args {
+		for _, arg := range list {
+			if !isFlag(arg) {
+				continue
+			}
+			switch flagName(arg) {
+			case "filter_complex", "filter_complex_script", "/filter_complex", "lavfi":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// maps retorna os valores de -map de todos os outputs.
+//
+// maps returns the -map values of every output.
+func (b *ffmpegBuilder) maps() []string {
+	var maps []string
+	for _, o := range b.outputs {
+		for i := 0; i+1 < len(o.args); i++ {
+			if o.args[i] == "-map" {
+				maps = append(maps, o.args[i+1])
+				i++
+			}
+		}
+	}
+	return maps
+}
+
+// Pad é uma referência a um pad do filtergraph: um stream de input (InputPad)
+// ou uma saída de cadeia criada por complexFilter.Link, com label gerado automaticamente.
+//
+// Pad is a reference to a filtergraph pad: an input stream (InputPad)
+// or a chain output created by complexFilter.Link, with an automatically generated label.
+type Pad struct {
+	label string
+}
+
+// InputPad retorna o pad do stream de um input, ex.: InputPad(0, Video) equivale a [0:v].
+// Um stream vazio referencia o input inteiro.
+//
+// InputPad returns the pad of an input stream, e.g. InputPad(0, Video) is equivalent to [0:v].
+// An empty stream references the whole input.
+func InputPad(index int, stream StreamType) Pad {
+	label := strconv.Itoa(index)
+	if stream != "" {
+		label += ":" + string(stream)
+	}
+	return Pad{label: label}
+}
+
+// Label retorna o label do pad sem colchetes.
+//
+// Label returns the pad label without brackets.
+func (p Pad) Label() string {
+	return p.label
+}
+
+// String retorna o pad no formato usado por -map e pelo filtergraph, ex.: "[p1]".
+//
+// String returns the pad in the format used by -map and the filtergraph, e.g. "[p1]".
+func (p Pad) String() string {
+	return "[" + p.label + "]"
+}
+
+func padLabels(pads []Pad) []string {
+	labels := make([]string, len(pads))
+	for i, p := range pads {
+		labels[i] = p.label
+	}
+	return labels
+}
+
+// newLabel gera um label único entre os já produzidos pelo graph.
+//
+// newLabel generates a label that is unique among the ones already produced by the graph.
+func (b *ffmpegBuilder) newLabel() string {
+	used := map[string]bool{}
+	for _, c := range b.graph().Chains {
+		for _, out := range c.Output {
+			used[out] = true
+		}
+	}
+	for {
+		b.padSeq++
+		label := "p" + strconv.Itoa(b.padSeq)
+		if !used[label] {
+			return label
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package fflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphValidate(t *testing.T) {
+	scale := AtomicFilter{Name: "scale", Params: []string{"1280", "-2"}}
+	overlay := AtomicFilter{Name: "overlay"}
+
+	tests := []struct {
+		name     string
+		graph    Graph
+		inputs   int
+		mapped   []string
+		expected []string
+	}{
+		{
+			name: "Graph válido",
+			graph: Graph{Chains: []Chain{
+				{Inputs: []string{"0:v"}, Filter: []AtomicFilter{scale}, Output: []string{"main"}},
+				{Inputs: []string{"main", "1:v"}, Filter: []AtomicFilter{overlay}, Output: []string{"out"}},
+			}},
+			inputs: 2,
+			mapped: []string{"[out]", "0:a"},
+		},
+		{
+			name: "Referência a input inexistente",
+			graph: Graph{Chains: []Chain{
+				{Inputs: []string{"0:v", "2:v"}, Filter: []AtomicFilter{overlay}},
+			}},
+			inputs:   2,
+			expected: []string{"graph: pad [2:v] references input 2, but only 2 inputs were added"},
+		},
+		{
+			name: "Label com erro de digitação",
+			graph: Graph{Chains: []Chain{
+				{Inputs: []string{"0:v"}, Filter: []AtomicFilter{scale}, Output: []string{"scaled"}},
+				{Inputs: []string{"scale"}, Filter: []AtomicFilter{overlay}, Output: []string{"out"}},
+			}},
+			inputs: 1,
+			mapped: []string{"[out]"},
+			expected: []string{
+				"graph: label [scale] is consumed but never produced",
+				"graph: label [scaled] is never consumed or mapped",
+			},
+		},
+		{
+			name: "Label consumido duas vezes",
+			graph: Graph{Chains: []Chain{
+				{Inputs: []string{"0:v"}, Filter: []AtomicFilter{scale}, Output: []string{"v"}},
+				{Inputs: []string{"v"}, Filter: []AtomicFilter{{Name: "hflip"}}, Output: []string{"a"}},
+				{Inputs: []string{"v"}, Filter: []AtomicFilter{{Name: "vflip"}}, Output: []string{"b"}},
+			}},
+			inputs:   1,
+			mapped:   []string{"[a]", "[b]"},
+			expected: []string{"graph: label [v] is consumed 2 times; use split or asplit"},
+		},
+		{
+			name: "Label produzido duas vezes",
+			graph: Graph{Chains: []Chain{
+				{Inputs: []string{"0:v"}, Filter: []AtomicFilter{scale}, Output: []string{"out"}},
+				{Inputs: []string{"1:v"}, Filter: []AtomicFilter{scale}, Output: []string{"out"}},
+			}},
+			inputs:   2,
+			mapped:   []string{"[out]"},
+			expected: []string{"graph: label [out] is produced 2 times"},
+		},
+		{
+			name: "Map para label desconhecido",
+			graph: Graph{Chains: []Chain{
+				{Inputs: []string{"0:v"}, Filter: []AtomicFilter{scale}, Output: []string{"out"}},
+			}},
+			inputs:   1,
+			mapped:   []string{"[out]", "[outv]"},
+			expected: []string{"graph: map [outv] references an unknown label"},
+		},
+		{
+			name: "Label inválido",
+			graph: Graph{Chains: []Chain{
+				{Inputs: []string{"0:v"}, Filter: []AtomicFilter{scale}, Output: []string{"a;b"}},
+			}},
+			inputs:   1,
+			expected: []string{`graph: invalid label "a;b"`},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.graph.Validate(tc.inputs, tc.mapped...)
+			if len(tc.expected) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, msg := range tc.expected {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+
+	t.Run("Validate no writeStage", func(t *testing.T) {
+		w := New().
+			Input("in.mp4").
+			Filter().
+			Complex().
+			Chain([]string{"0:v"}, []AtomicFilter{scale}, []string{"scaled"}).
+			Chain([]string{"scaled", "1:v"}, []AtomicFilter{overlay}, []string{"out"}).
+			Done().
+			Map("[out]").
+			Output("out.mp4")
+
+		err := w.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "references input 1, but only 1 inputs were added")
+
+		ok := New().
+			Input("in.mp4").
+			Filter().
+			Complex().
+			Chain([]string{"0:v"}, []AtomicFilter{scale}, []string{"out"}).
+			Done().
+			Map("[out]").
+			Output("out.mp4")
+
+		assert.NoError(t, ok.Validate())
+		assert.Equal(t, "[0:v]scale=1280:-2[out]", Graph{Chains: []Chain{{Inputs: []string{"0:v"}, Filter: []AtomicFilter{scale}, Output: []string{"out"}}}}.String())
+	})
+}
+
+func TestComplexFilterErr(t *testing.T) {
+	scale := AtomicFilter{Name: "scale", Params: []string{"1280", "-2"}}
+	overlay := AtomicFilter{Name: "overlay"}
+
+	t.Run("Chain registra o primeiro erro de ligação", func(t *testing.T) {
+		cf := New().
+			Input("in.mp4").
+			Filter().
+			Complex().
+			Chain([]string{"0:v"}, []AtomicFilter{scale}, []string{"scaled"})
+		require.NoError(t, cf.Err())
+
+		cf = cf.Chain([]string{"scaled", "1:v"}, []AtomicFilter{overlay}, []string{"out"}).
+			Chain([]string{"scaled"}, []AtomicFilter{scale}, []string{"out"})
+		assert.EqualError(t, cf.Err(), "graph: pad [1:v] references input 1, but only 1 inputs were added")
+	})
+
+	t.Run("Labels duplicados são detectados ao adicionar a cadeia", func(t *testing.T) {
+		cf := New().
+			Input("in.mp4").
+			Filter().
+			Complex().
+			Chain([]string{"0:v"}, []AtomicFilter{scale}, []string{"v"}).
+			Chain([]string{"v"}, []AtomicFilter{{Name: "hflip"}}, []string{"a"}).
+			Chain([]string{"v"}, []AtomicFilter{{Name: "vflip"}}, []string{"b"})
+		assert.EqualError(t, cf.Err(), "graph: label [v] is consumed 2 times; use split or asplit")
+
+		cf = New().
+			Input("in.mp4").
+			Filter().
+			Complex().
+			Chain([]string{"0:v"}, []AtomicFilter{scale}, []string{"out"}).
+			Chain([]string{"0:v"}, []AtomicFilter{scale}, []string{"out"})
+		assert.EqualError(t, cf.Err(), "graph: label [out] is produced 2 times")
+	})
+
+	t.Run("Done confere labels nunca produzidos", func(t *testing.T) {
+		cf := New().
+			Input("in.mp4").
+			Filter().
+			Complex().
+			Chain([]string{"main"}, []AtomicFilter{scale}, []string{"out"}).
+			Chain([]string{"0:v"}, []AtomicFilter{scale}, []string{"main"})
+		require.NoError(t, cf.Err(), "labels podem ser consumidos antes de serem produzidos")
+
+		cf = New().
+			Input("in.mp4").
+			Filter().
+			Complex().
+			Chain([]string{"0:v"}, []AtomicFilter{scale}, []string{"scaled"}).
+			Chain([]string{"scale"}, []AtomicFilter{overlay}, []string{"out"})
+		require.NoError(t, cf.Err())
+
+		w := cf.Done().Map("[out]").Output("out.mp4")
+		assert.EqualError(t, cf.Err(), "graph: label [scale] is consumed but never produced")
+		assert.ErrorContains(t, w.Validate(), "graph: label [scaled] is never consumed or mapped")
+	})
+}
+
+func TestPads(t *testing.T) {
+	scale := AtomicFilter{Name: "scale", Params: []string{"1280", "-2"}}
+	overlay := AtomicFilter{Name: "overlay"}
+
+	t.Run("InputPad", func(t *testing.T) {
+		assert.Equal(t, "[0:v]", InputPad(0, Video).String())
+		assert.Equal(t, "[1:a]", InputPad(1, Audio).String())
+		assert.Equal(t, "2", InputPad(2, "").Label())
+	})
+
+	t.Run("Link gera labels únicos", func(t *testing.T) {
+		var scaled, out Pad
+		w := New().
+			Input("in.mp4").
+			Input("logo.png").
+			Filter().
+			Complex().
+			Chain([]string{"1:v"}, []AtomicFilter{{Name: "format", Params: []string{"rgba"}}}, []string{"p1"}).
+			Link([]Pad{InputPad(0, Video)}, []AtomicFilter{scale}, &scaled).
+			Link([]Pad{scaled, {label: "p1"}}, []AtomicFilter{overlay}, &out).
+			Done().
+			MapPad(out).
+			Map("0:a").
+			Output("out.mp4")
+
+		assert.Equal(t, "p2", scaled.Label())
+		assert.Equal(t, "p3", out.Label())
+		assert.Equal(t, "-loglevel error -y -i in.mp4 -i logo.png "+
+			"-filter_complex [1:v]format=rgba[p1];[0:v]scale=1280:-2[p2];[p2][p1]overlay[p3] "+
+			"-map [p3] -map 0:a out.mp4", w.String())
+		assert.NoError(t, w.Validate())
+	})
+
+	t.Run("Link com várias saídas", func(t *testing.T) {
+		var a, b Pad
+		w := New().
+			Input("in.mp4").
+			Filter().
+			Complex().
+			Link([]Pad{InputPad(0, Video)}, []AtomicFilter{{Name: "split"}}, &a, &b).
+			Done().
+			MapPad(a).
+			Output("a.mp4").
+			NextOutput().
+			MapPad(b).
+			Output("b.mp4")
+
+		assert.Equal(t, "-loglevel error -y -i in.mp4 -filter_complex [0:v]split[p1][p2] "+
+			"-map [p1] a.mp4 -map [p2] b.mp4", w.String())
+		assert.NoError(t, w.Validate())
+	})
+
+	t.Run("Pad não inicializado é reportado", func(t *testing.T) {
+		var missing, out Pad
+		w := New().
+			Input("in.mp4").
+			Filter().
+			Complex().
+			Link([]Pad{missing}, []AtomicFilter{scale}, &out).
+			Done().
+			MapPad(out).
+			Output("out.mp4")
+
+		assert.ErrorContains(t, w.Validate(), `graph: invalid label ""`)
+	})
+
+	t.Run("Pad nil é reportado sem pânico", func(t *testing.T) {
+		var b Pad
+		var cf complexFilter
+		require.NotPanics(t, func() {
+			cf = New().
+				Input("in.mp4").
+				Filter().
+				Complex().
+				Link([]Pad{InputPad(0, Video)}, []AtomicFilter{{Name: "split"}}, nil, &b)
+		})
+
+		assert.Equal(t, "p2", b.Label())
+		assert.EqualError(t, cf.Err(), "graph: Link output 0 is a nil *Pad; label [p1] cannot be referenced")
+
+		w := cf.Done().MapPad(b).Output("out.mp4")
+		assert.Equal(t, "-loglevel error -y -i in.mp4 -filter_complex [0:v]split[p1][p2] -map [p2] out.mp4", w.String())
+		assert.ErrorContains(t, w.Validate(), "graph: label [p1] is never consumed or mapped")
+	})
+}
@@ -0,0 +1,231 @@
+package fflow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// LogLine é uma linha de log escrita pelo ffmpeg no stderr.
+//
+// LogLine is a log line written by ffmpeg to stderr.
+type LogLine struct {
+	// Component é o contexto que emitiu a linha, sem colchetes (ex.: "libx264 @ 0x5581c0"),
+	// ou vazio para mensagens gerais.
+	//
+	// Component is the context that emitted the line, without brackets
+	// (e.g. "libx264 @ 0x5581c0"), or empty for general messages.
+	Component string
+
+	// Level é o nível da linha ("error", "warning", "info"...) quando o ffmpeg roda com
+	// -loglevel level+... (ver WithLogger), ou vazio.
+	//
+	// Level is the line level ("error", "warning", "info"...) when ffmpeg runs with
+	// -loglevel level+... (see WithLogger), or empty.
+	Level string
+
+	// Message é o texto da linha sem o prefixo do componente.
+	//
+	// Message is the line text without the component prefix.
+	Message string
+
+	// Raw é a linha original, como o ffmpeg a escreveu.
+	//
+	// Raw is the original line, as ffmpeg wrote it.
+	Raw string
+}
+
+// WithLogFunc define uma função chamada para cada linha do stderr do ffmpeg em Run,
+// RunWithProgress e RunWithProgressFunc. Quando definida, Run deixa de copiar o stderr
+// para os.Stderr. fn pode ser chamada em uma goroutine diferente da função de progresso.
+//
+// WithLogFunc sets a function called for each ffmpeg stderr line in Run,
+// RunWithProgress and RunWithProgressFunc. When set, Run no longer copies stderr
+// to os.Stderr. fn may be called on a different goroutine than the progress function.
+func WithLogFunc(fn func(LogLine)) Option {
+	return func(b *ffmpegBuilder) { b.logFunc = fn }
+}
+
+// WithLogger envia cada linha do stderr do ffmpeg para logger como um registro slog,
+// com o nível da linha, a mensagem e o atributo "component". O -loglevel passa a ser
+// emitido como repeat+level+<nível> para que o ffmpeg informe o nível de cada linha.
+// Quando definido, Run deixa de copiar o stderr para os.Stderr.
+//
+// WithLogger sends each ffmpeg stderr line to logger as an slog record, with the
+// line level, the message and the "component" attribute. -loglevel is then emitted
+// as repeat+level+<level> so that ffmpeg reports the level of each line.
+// When set, Run no longer copies stderr to os.Stderr.
+func WithLogger(logger *slog.Logger) Option {
+	return func(b *ffmpegBuilder) { b.logger = logger }
+}
+
+// logLevels associa os níveis do ffmpeg aos níveis do slog.
+//
+// logLevels maps ffmpeg levels to slog levels.
+var logLevels = map[string]slog.Level{
+	"panic":   slog.LevelError,
+	"fatal":   slog.LevelError,
+	"error":   slog.LevelError,
+	"warning": slog.LevelWarn,
+	"info":    slog.LevelInfo,
+	"verbose": slog.LevelDebug,
+	"debug":   slog.LevelDebug,
+	"trace":   slog.LevelDebug,
+}
+
+// setLogLevel substitui o valor de -loglevel (ou -v) nos argumentos globais, ou o
+// adiciona quando ainda não existe.
+//
+// setLogLevel replaces the -loglevel (or -v) value in the global arguments, or adds
+// it when it does not exist yet.
+func (b *ffmpegBuilder) setLogLevel(level string) {
+	if i := logLevelIndex(b.global); i >= 0 {
+		b.global[i] = level
+		return
+	}
+	b.global = append([]string{"-loglevel", level}, b.global...)
+}
+
+// globalArgs retorna os argumentos globais. Com WithLogger, o nível recebe as flags
+// repeat+level, preservando o nível escolhido.
+//
+// globalArgs returns the global arguments. With WithLogger, the level gets the
+// repeat+level flags, keeping the chosen level.
+func (b *ffmpegBuilder) globalArgs() []string {
+	if b.logger == nil {
+		return b.global
+	}
+
+	global := slices.Clone(b.global)
+	i := logLevelIndex(global)
+	if i < 0 {
+		return append([]string{"-loglevel", "repeat+level+info"}, global...)
+	}
+
+	// INFO: o valor pode já ter flags (ex.: "+repeat+error"); o nível é sempre o último item.
+	level := global[i]
+	if j := strings.LastIndex(level, "+"); j >= 0 {
+		level = level[j+1:]
+	}
+	global[i] = "repeat+level+" + level
+	return global
+}
+
+// logLevelIndex retorna a posição do valor de -loglevel (ou -v) em args, ou -1.
+//
+// logLevelIndex returns the position of the -loglevel (or -v) value in args, or -1.
+func logLevelIndex(args []string) int {
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == "-loglevel" || args[i] == "-v" {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// parseLogLine separa os prefixos "[componente @ 0x...]" e "[nível]" da mensagem.
+// Quando há mais de um componente (contexto pai e filho), o último é mantido.
+//
+// parseLogLine splits the "[component @ 0x...]" and "[level]" prefixes from the message.
+// When there is more than one component (parent and child context), the last one is kept.
+func parseLogLine(raw string) LogLine {
+	line := LogLine{Message: raw, Raw: raw}
+	for strings.HasPrefix(line.Message, "[") {
+		end := strings.Index(line.Message, "] ")
+		if end < 0 {
+			break
+		}
+
+		prefix := line.Message[1:end]
+		line.Message = line.Message[end+2:]
+		if _, ok := logLevels[prefix]; ok {
+			line.Level = prefix
+			break
+		}
+		line.Component = prefix
+	}
+	return line
+}
+
+// maxLogLine é o tamanho máximo de uma linha do stderr. Linhas maiores interrompem a
+// leitura, e o restante do stderr é descartado.
+//
+// maxLogLine is the maximum size of a stderr line. Longer lines stop the reading,
+// and the rest of stderr is discarded.
+const maxLogLine = 1 << 20
+
+// newLogScanner retorna um scanner que separa o stderr em linhas terminadas por "\n",
+// "\r\n" ou "\r", usado pelas estatísticas do ffmpeg (frame=... speed=...) quando
+// -nostats não é usado.
+//
+// newLogScanner returns a scanner that splits stderr into lines ended by "\n", "\r\n"
+// or "\r", used by ffmpeg statistics (frame=... speed=...) when -nostats is not used.
+func newLogScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLogLine)
+	scanner.Split(scanLogLines)
+	return scanner
+}
+
+// scanLogLines é a bufio.SplitFunc de newLogScanner.
+//
+// scanLogLines is the bufio.SplitFunc of newLogScanner.
+func scanLogLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	i := bytes.IndexAny(data, "\r\n")
+	if i < 0 {
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+	if data[i] == '\r' {
+		if i+1 < len(data) && data[i+1] == '\n' {
+			return i + 2, data[:i], nil
+		}
+		// INFO: o "\n" de um "\r\n" pode chegar na próxima leitura.
+		if i+1 == len(data) && !atEOF {
+			return 0, nil, nil
+		}
+	}
+	return i + 1, data[:i], nil
+}
+
+// readLog lê o stderr linha a linha, guardando cada uma em tail e repassando para
+// o logFunc e o logger configurados.
+//
+// readLog reads stderr line by line, keeping each one in tail and forwarding it to
+// the configured logFunc and logger.
+func (c *commandCtx) readLog(ctx context.Context, stderr io.Reader, tail *stderrTail) {
+	scanner := newLogScanner(stderr)
+	for scanner.Scan() {
+		c.logLine(ctx, scanner.Text(), tail)
+	}
+	_, _ = io.Copy(io.Discard, stderr)
+}
+
+func (c *commandCtx) logLine(ctx context.Context, raw string, tail *stderrTail) {
+	tail.Add(raw)
+	if c.b.logFunc == nil && c.b.logger == nil {
+		return
+	}
+
+	line := parseLogLine(raw)
+	if c.b.logFunc != nil {
+		c.b.logFunc(line)
+	}
+	if c.b.logger != nil {
+		level, ok := logLevels[line.Level]
+		if !ok {
+			level = slog.LevelInfo
+		}
+		var attrs []slog.Attr
+		if line.Component != "" {
+			attrs = append(attrs, slog.String("component", line.Component))
+		}
+		c.b.logger.LogAttrs(ctx, level, line.Message, attrs...)
+	}
+}
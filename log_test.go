@@ -0,0 +1,168 @@
+package fflow
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordHandler guarda os registros recebidos pelo slog.
+type recordHandler struct{ records []slog.Record }
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler       { return h }
+func (h *recordHandler) WithGroup(string) slog.Handler            { return h }
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records = append(h.records, r)
+	return nil
+}
+
+func TestLog(t *testing.T) {
+	t.Run("parseLogLine", func(t *testing.T) {
+		tests := []struct {
+			raw      string
+			expected LogLine
+		}{
+			{"frame=1", LogLine{Message: "frame=1"}},
+			{"[libx264 @ 0x1] using SAR=1/1", LogLine{Component: "libx264 @ 0x1", Message: "using SAR=1/1"}},
+			{"[warning] deprecated pixel format used", LogLine{Level: "warning", Message: "deprecated pixel format used"}},
+			{"[mp4 @ 0x1] [h264 @ 0x2] [error] non monotonic DTS", LogLine{Component: "h264 @ 0x2", Level: "error", Message: "non monotonic DTS"}},
+			{"[info] [not a level] text", LogLine{Level: "info", Message: "[not a level] text"}},
+			{"[incompleto", LogLine{Message: "[incompleto"}},
+		}
+
+		for _, tt := range tests {
+			tt.expected.Raw = tt.raw
+			assert.Equal(t, tt.expected, parseLogLine(tt.raw), tt.raw)
+		}
+	})
+
+	t.Run("Separa linhas por \\r e \\n", func(t *testing.T) {
+		var lines []string
+		scanner := newLogScanner(strings.NewReader("a\rb\r\nc\nd"))
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		require.NoError(t, scanner.Err())
+		assert.Equal(t, []string{"a", "b", "c", "d"}, lines)
+	})
+
+	t.Run("Estatísticas longas não escondem o erro final", func(t *testing.T) {
+		stats := strings.Repeat("frame=  100 fps= 25 q=28.0 size=    1024kB time=00:00:04.00 bitrate=2097.2kbits/s speed=1x    \r", 2000)
+		fake := &fakeExecutor{
+			stderr: stats + "[error] Conversion failed!\n",
+			err:    errors.New("exit status 1"),
+		}
+
+		var last LogLine
+		err := New(WithExecutor(fake), WithLogFunc(func(l LogLine) { last = l })).
+			Input("in.mp4").
+			Output("out.mp4").
+			Command().
+			Run(context.Background())
+
+		require.Greater(t, len(stats), 64*1024)
+		assert.ErrorContains(t, err, "Conversion failed!")
+		assert.Equal(t, "error", last.Level)
+		assert.Equal(t, "Conversion failed!", last.Message)
+	})
+
+	t.Run("LogLevel e WithLogger", func(t *testing.T) {
+		parsed, err := ParseArgs([]string{"-i", "in.mp4", "out.mp4"}, WithLogger(slog.Default()))
+		require.NoError(t, err)
+		verbose, err := ParseArgs([]string{"-v", "quiet", "-i", "in.mp4", "out.mp4"})
+		require.NoError(t, err)
+
+		tests := []struct {
+			name     string
+			builder  writeStage
+			expected string
+		}{
+			{
+				name:     "WithLogger mantém o nível padrão",
+				builder:  New(WithLogger(slog.Default())).Input("in.mp4").Output("out.mp4"),
+				expected: "-loglevel repeat+level+error -y -i in.mp4 out.mp4",
+			},
+			{
+				name:     "WithLogger com LogLevel",
+				builder:  New(WithLogger(slog.Default())).LogLevel("warning").Input("in.mp4").Output("out.mp4"),
+				expected: "-loglevel repeat+level+warning -y -i in.mp4 out.mp4",
+			},
+			{
+				name:     "Flags existentes são substituídas",
+				builder:  New(WithLogger(slog.Default())).LogLevel("+repeat+verbose").Input("in.mp4").Output("out.mp4"),
+				expected: "-loglevel repeat+level+verbose -y -i in.mp4 out.mp4",
+			},
+			{
+				name:     "Sem -loglevel usa info",
+				builder:  parsed,
+				expected: "-loglevel repeat+level+info -i in.mp4 out.mp4",
+			},
+			{
+				name:     "Sem WithLogger o nível fica inalterado",
+				builder:  verbose,
+				expected: "-v quiet -i in.mp4 out.mp4",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, tt.builder.String())
+			})
+		}
+	})
+
+	t.Run("LogLevel adiciona -loglevel quando ausente", func(t *testing.T) {
+		b := newBuilder([]string{"-y"})
+		b.setLogLevel("debug")
+		assert.Equal(t, []string{"-loglevel", "debug", "-y"}, b.global)
+
+		b.setLogLevel("info")
+		assert.Equal(t, []string{"-loglevel", "info", "-y"}, b.global)
+	})
+
+	t.Run("WithLogger recebe registros com nível e componente", func(t *testing.T) {
+		fake := &fakeExecutor{
+			stderr: "[mp4 @ 0x1] [warning] Non-monotonic DTS; previous: 10, current: 9\n" +
+				"[error] Conversion failed!\n" +
+				"sem prefixo\n",
+			progress: "progress=end\n",
+		}
+
+		h := &recordHandler{}
+		err := New(WithExecutor(fake), WithLogger(slog.New(h))).
+			LogLevel("warning").
+			Input("in.mp4").
+			Output("out.mp4").
+			Command().
+			RunWithProgressFunc(context.Background(), func(Progress) {})
+
+		require.NoError(t, err)
+		assert.Equal(t, "repeat+level+warning", fake.spec.Args[1])
+		require.Len(t, h.records, 3)
+
+		assert.Equal(t, slog.LevelWarn, h.records[0].Level)
+		assert.Equal(t, "Non-monotonic DTS; previous: 10, current: 9", h.records[0].Message)
+		assert.Equal(t, map[string]string{"component": "mp4 @ 0x1"}, recordAttrs(h.records[0]))
+
+		assert.Equal(t, slog.LevelError, h.records[1].Level)
+		assert.Empty(t, recordAttrs(h.records[1]))
+
+		assert.Equal(t, slog.LevelInfo, h.records[2].Level)
+		assert.Equal(t, "sem prefixo", h.records[2].Message)
+	})
+}
+
+func recordAttrs(r slog.Record) map[string]string {
+	attrs := map[string]string{}
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.String()
+		return true
+	})
+	return attrs
+}
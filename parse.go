@@ -0,0 +1,401 @@
+package fflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// globalFlags são as opções que afetam o programa inteiro e vão para a lista global,
+// independentemente da posição em que aparecem.
+//
+// globalFlags are the options that affect the whole program and go to the global list,
+// regardless of where they appear.
+var globalFlags = map[string]bool{
+	"y": true, "n": true, "loglevel": true, "v": true, "hide_banner": true,
+	"stats": true, "nostats": true, "stats_period": true, "progress": true,
+	"stdin": true, "nostdin": true, "filter_threads": true, "filter_complex_threads": true,
+	"benchmark": true, "benchmark_all": true, "report": true, "xerror": true,
+	"abort_on": true, "max_error_rate": true, "sdp_file": true, "init_hw_device": true,
+	"filter_hw_device": true, "ignore_unknown": true, "copy_unknown": true,
+	"debug_ts": true, "cpuflags": true, "max_alloc": true, "vstats": true, "vstats_file": true,
+	"psnr": true, "qphist": true,
+	"filter_complex": true, "lavfi": true, "filter_complex_script": true, "/filter_complex": true,
+}
+
+// booleanFlags são as opções que não recebem valor. As demais recebem o próximo token,
+// a menos que ele também seja uma flag (ver takesValue).
+//
+// booleanFlags are the options that take no value. The others take the next token,
+// unless it is a flag as well (see takesValue).
+var booleanFlags = map[string]bool{
+	"y": true, "n": true, "hide_banner": true, "stats": true, "nostats": true,
+	"stdin": true, "nostdin": true, "benchmark": true, "benchmark_all": true,
+	"report": true, "xerror": true, "ignore_unknown": true, "copy_unknown": true,
+	"debug_ts": true, "re": true, "an": true, "vn": true, "sn": true, "dn": true,
+	"shortest": true, "copyts": true, "start_at_zero": true, "copyinkf": true,
+	"autorotate": true, "noautorotate": true, "autoscale": true, "noautoscale": true,
+	"accurate_seek": true, "noaccurate_seek": true, "fix_sub_duration": true,
+	"bitexact": true, "dump": true, "hex": true, "vstats": true, "psnr": true,
+	"qphist": true, "ignore_chapters": true, "seek_timestamp": true,
+	"recast_media": true, "find_stream_info": true,
+}
+
+// Parse interpreta uma linha de comando do ffmpeg, com aspas no estilo do shell, e
+// reconstrói o builder equivalente. O primeiro token é usado como binário quando não
+// é uma flag; options aplicadas depois podem substituí-lo (WithBinary).
+// Ao contrário de New, o builder começa sem opções globais.
+//
+// Parse parses an ffmpeg command line, with shell-style quoting, and rebuilds the
+// equivalent builder. The first token is used as the binary when it is not a flag;
+// options applied afterwards can replace it (WithBinary).
+// Unlike New, the builder starts without global options.
+func Parse(cmd string, opts ...Option) (writeStage, error) {
+	args, err := splitCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) > 0 && !isFlag(args[0]) {
+		opts = append([]Option{WithBinary(args[0])}, opts...)
+		args = args[1:]
+	}
+	return ParseArgs(args, opts...)
+}
+
+// ParseArgs reconstrói o builder a partir dos argumentos do ffmpeg, sem o binário,
+// de forma que ParseArgs(w.Args()) gere os mesmos Args. Cada flag é classificada como
+// global, de input (antes de -i), de filtro ou de output (antes do arquivo de saída).
+// Flags desconhecidas são mantidas como Raw na mesma posição (sem valor quando o próximo
+// token é outra flag, ex.: -vstats -i, ou o último token, que é sempre o arquivo de saída),
+// e -filter_complex, -vf, -af e -filter:<stream> são convertidos em cadeias quando possível.
+//
+// ParseArgs rebuilds the builder from ffmpeg arguments, without the binary, so that
+// ParseArgs(w.Args()) produces the same Args. Each flag is classified as global,
+// input (before -i), filter or output (before the output file). Unknown flags are
+// kept as Raw in the same position (without a value when the next token is another
+// flag, e.g. -vstats -i, or the last token, which is always the output file), and
+// -filter_complex, -vf, -af and -filter:<stream> are turned into chains when possible.
+func ParseArgs(args []string, opts ...Option) (writeStage, error) {
+	b := newBuilder(nil, opts...)
+
+	var pending [][]string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !isFlag(arg) {
+			b.addParsedOutput(arg, pending)
+			pending = nil
+			continue
+		}
+
+		name := flagName(arg)
+		option := []string{arg}
+		if !booleanFlags[name] {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("parse: flag %s expects a value", arg)
+			}
+			// INFO: o último token é o arquivo de saída; só -i e flags globais podem
+			// tomá-lo como valor (ex.: "-seek_timestamp out.mp4" não tem valor).
+			last := i+2 == len(args) && arg != "-i" && !globalFlags[name]
+			if takesValue(args[i+1]) && !last {
+				i++
+				option = append(option, args[i])
+			}
+		}
+
+		switch {
+		case arg == "-i":
+			b.pending = append(b.pending, flatten(pending)...)
+			b.addInput(option[1])
+			pending = nil
+		case name == "filter_complex" || name == "lavfi":
+			chains, ok := parseGraph(option[1])
+			if !ok {
+				// INFO: grafo que Chain não representa fica cru na posição original,
+				// depois das entradas já lidas.
+				pending = append(pending, option)
+				continue
+			}
+			for _, c := range chains {
+				b.filters = append(b.filters, c)
+			}
+		case globalFlags[name]:
+			b.global = append(b.global, option...)
+		default:
+			pending = append(pending, option)
+		}
+	}
+
+	if len(pending) > 0 {
+		return nil, fmt.Errorf("parse: trailing options without an output file: %s", strings.Join(flatten(pending), " "))
+	}
+	return &writeCtx{b}, nil
+}
+
+// addParsedOutput registra um output com as opções lidas antes do seu arquivo,
+// convertendo as flags de filtro simples em cadeias.
+//
+// addParsedOutput registers an output with the options read before its file,
+// turning simple filter flags into chains.
+func (b *ffmpegBuilder) addParsedOutput(path string, options [][]string) {
+	o := b.newOutput()
+	o.path = path
+	for _, option := range options {
+		if len(option) == 2 && isSimpleFilterFlag(option[0]) {
+			if chains, ok := parseGraph(option[1]); ok && len(chains) == 1 &&
+				len(chains[0].Inputs) == 0 && len(chains[0].Output) == 0 {
+				c := o.chain(SimpleFilterType(option[0]))
+				c.filters = append(c.filters, chains[0].Filter...)
+				continue
+			}
+		}
+		o.args = append(o.args, option...)
+	}
+}
+
+func isFlag(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-'
+}
+
+// takesValue informa se next pode ser o valor de uma flag que não está em booleanFlags.
+// Outra flag indica que a anterior não recebe valor, exceto números negativos
+// (-stream_loop -1, -itsoffset -00:00:02.500).
+//
+// takesValue reports whether next can be the value of a flag that is not in booleanFlags.
+// Another flag means the previous one takes no value, except negative numbers
+// (-stream_loop -1, -itsoffset -00:00:02.500).
+func takesValue(next string) bool {
+	if !isFlag(next) {
+		return true
+	}
+	c := next[1]
+	return c >= '0' && c <= '9' || c == '.'
+}
+
+// flagName retorna o nome da flag sem o hífen e sem o especificador de stream (-c:v -> c).
+//
+// flagName returns the flag name without the dash and the stream specifier (-c:v -> c).
+func flagName(arg string) string {
+	name, _, _ := strings.Cut(arg[1:], ":")
+	return name
+}
+
+func isSimpleFilterFlag(arg string) bool {
+	return arg == string(FilterVideo) || arg == string(FilterAudio) || flagName(arg) == "filter"
+}
+
+func flatten(options [][]string) []string {
+	var args []string
+	for _, option := range options {
+		args = append(args, option...)
+	}
+	return args
+}
+
+// splitCommand divide uma linha de comando em argumentos, seguindo as regras de aspas
+// do shell: aspas simples são literais, aspas duplas aceitam \" e \\, e a barra
+// invertida fora de aspas escapa o próximo caractere.
+//
+// splitCommand splits a command line into arguments, following shell quoting rules:
+// single quotes are literal, double quotes accept \" and \\, and a backslash
+// outside quotes escapes the next character.
+func splitCommand(cmd string) ([]string, error) {
+	var (
+		args    []string
+		sb      strings.Builder
+		inToken bool
+		quote   rune
+	)
+
+	runes := []rune(cmd)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				sb.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune(`"\$`+"`", runes[i+1]):
+				i++
+				sb.WriteRune(runes[i])
+			case r == '\\' && i+1 < len(runes) && runes[i+1] == '\n':
+				i++
+			default:
+				sb.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inToken = true
+		case r == '\\':
+			if i+1 < len(runes) {
+				i++
+				if runes[i] != '\n' {
+					sb.WriteRune(runes[i])
+					inToken = true
+				}
+			}
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inToken {
+				args = append(args, sb.String())
+				sb.Reset()
+				inToken = false
+			}
+		default:
+			sb.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("parse: unterminated %c quote", quote)
+	}
+	if inToken {
+		args = append(args, sb.String())
+	}
+	return args, nil
+}
+
+// parseGraph converte a descrição de um filtergraph em cadeias, desfazendo o escape do
+// graph (o inverso de AtomicFilter.String). Retorna false para sintaxes que Chain não
+// representa, como labels no meio de uma cadeia.
+//
+// parseGraph turns a filtergraph description into chains, undoing the graph escaping
+// (the inverse of AtomicFilter.String). It returns false for syntax that Chain cannot
+// represent, such as labels in the middle of a chain.
+func parseGraph(desc string) ([]Chain, bool) {
+	var chains []Chain
+	p := graphParser{s: []rune(desc)}
+
+	for {
+		var c Chain
+		c.Inputs = p.labels()
+		for {
+			f, ok := p.filter()
+			if !ok {
+				return nil, false
+			}
+			c.Filter = append(c.Filter, f)
+			if !p.consume(',') {
+				break
+			}
+		}
+		c.Output = p.labels()
+		chains = append(chains, c)
+
+		p.skipSpace()
+		if p.done() {
+			return chains, true
+		}
+		if !p.consume(';') {
+			return nil, false
+		}
+	}
+}
+
+type graphParser struct {
+	s   []rune
+	pos int
+}
+
+func (p *graphParser) done() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *graphParser) skipSpace() {
+	for !p.done() && strings.ContainsRune(" \t\n\r", p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *graphParser) consume(r rune) bool {
+	p.skipSpace()
+	if !p.done() && p.s[p.pos] == r {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *graphParser) labels() []string {
+	var labels []string
+	for p.consume('[') {
+		start := p.pos
+		for !p.done() && p.s[p.pos] != ']' {
+			p.pos++
+		}
+		labels = append(labels, string(p.s[start:p.pos]))
+		p.pos++
+	}
+	return labels
+}
+
+// filter lê a descrição de um filtro até um separador do graph que não esteja escapado.
+//
+// filter reads a filter description up to an unescaped graph separator.
+func (p *graphParser) filter() (AtomicFilter, bool) {
+	p.skipSpace()
+
+	var sb strings.Builder
+	for !p.done() {
+		r := p.s[p.pos]
+		if strings.ContainsRune("[],;", r) {
+			break
+		}
+		p.pos++
+		switch r {
+		case '\\':
+			if !p.done() {
+				sb.WriteRune(p.s[p.pos])
+				p.pos++
+			}
+		case '\'':
+			for !p.done() && p.s[p.pos] != '\'' {
+				sb.WriteRune(p.s[p.pos])
+				p.pos++
+			}
+			p.pos++
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	desc := strings.TrimSpace(sb.String())
+	name, opts, hasOpts := strings.Cut(desc, "=")
+	if name == "" {
+		return AtomicFilter{}, false
+	}
+	f := AtomicFilter{Name: name}
+	if hasOpts {
+		f.Params = splitParams(opts)
+	}
+	return f, true
+}
+
+// splitParams separa as opções de um filtro nos ':' que não estão escapados nem entre aspas.
+//
+// splitParams splits filter options at ':' that are neither escaped nor quoted.
+func splitParams(opts string) []string {
+	var (
+		params []string
+		start  int
+		quoted bool
+	)
+	for i := 0; i < len(opts); i++ {
+		switch opts[i] {
+		case '\\':
+			i++
+		case '\'':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				params = append(params, opts[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(params, opts[start:])
+}
@@ -0,0 +1,182 @@
+package fflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      string
+		expected []string
+	}{
+		{"Espaços", "ffmpeg  -i in.mp4\tout.mp4", []string{"ffmpeg", "-i", "in.mp4", "out.mp4"}},
+		{"Aspas simples", `-vf 'drawtext=text=a b'`, []string{"-vf", "drawtext=text=a b"}},
+		{"Aspas duplas", `-metadata "title=\"My\" \\ clip"`, []string{"-metadata", `title="My" \ clip`}},
+		{"Barra invertida", `my\ file.mp4`, []string{"my file.mp4"}},
+		{"Continuação de linha", "-i in.mp4 \\\n out.mp4", []string{"-i", "in.mp4", "out.mp4"}},
+		{"Token vazio", `-metadata ''`, []string{"-metadata", ""}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			args, err := splitCommand(tc.cmd)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, args)
+		})
+	}
+
+	_, err := splitCommand(`-vf "scale=1:2`)
+	assert.Error(t, err)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("Round-trip dos Args do builder", func(t *testing.T) {
+		var scaled, out Pad
+		builders := []writeStage{
+			New().Input("in.mp4").Output("out.mp4"),
+			New().
+				Ss(5*time.Second).
+				Input("in.mp4", InputFormat("mp4"), InputRe()).
+				Input("logo.png", InputStreamLoop(-1)).
+				Filter().
+				Complex().
+				Link([]Pad{InputPad(0, Video)}, []AtomicFilter{{Name: "scale", Params: []string{"1280", "-2"}}}, &scaled).
+				Link([]Pad{scaled, InputPad(1, Video)}, []AtomicFilter{{Name: "overlay", Params: []string{"x=W-w-10", "y=10"}}}, &out).
+				Done().
+				MapPad(out).
+				Map("0:a").
+				VideoCodec("libx264").
+				CRF(23).
+				Output("out.mp4"),
+			New().
+				Input("in.mkv").
+				Filter().
+				Simple(FilterVideo).
+				Add(AtomicFilter{Name: "drawtext", Params: []string{Param("text", "Time: 10, ok")}}).
+				Done().
+				Filter().
+				Simple(FilterStream(Audio, 1)).
+				Add(AtomicFilter{Name: "volume", Params: []string{"0.5"}}).
+				Done().
+				Raw("-shortest").
+				Output("a.mkv").
+				NextOutput().
+				CopyVideo().
+				Output("b.mkv"),
+		}
+
+		for _, w := range builders {
+			parsed, err := ParseArgs(w.Args())
+			require.NoError(t, err)
+			assert.Equal(t, w.Args(), parsed.Args())
+		}
+	})
+
+	t.Run("Classifica flags de uma linha de comando legada", func(t *testing.T) {
+		w, err := Parse(`/usr/bin/ffmpeg -hide_banner -ss 10 -i "my video.mp4" -i music.mp3 ` +
+			`-filter_complex "[0:v]scale=1280:-2[v];[1:a]volume=0.5[a]" -map '[v]' -map '[a]' ` +
+			`-c:v libx264 -movflags +faststart out.mp4 -y`)
+		require.NoError(t, err)
+
+		b := w.(*writeCtx).b
+		assert.Equal(t, "/usr/bin/ffmpeg", b.binary)
+		assert.Equal(t, []string{"-hide_banner", "-y"}, b.global)
+		require.Len(t, b.inputs, 2)
+		assert.Equal(t, []string{"-ss", "10"}, b.inputs[0].args)
+		assert.Equal(t, "my video.mp4", b.inputs[0].path)
+		require.Len(t, b.graph().Chains, 2)
+		assert.Equal(t, AtomicFilter{Name: "scale", Params: []string{"1280", "-2"}}, b.graph().Chains[0].Filter[0])
+		require.Len(t, b.outputs, 1)
+		assert.Equal(t, []string{"-map", "[v]", "-map", "[a]", "-c:v", "libx264", "-movflags", "+faststart"}, b.outputs[0].args)
+		assert.NoError(t, w.Validate())
+	})
+
+	t.Run("Flags sem valor desconhecidas", func(t *testing.T) {
+		w, err := Parse("ffmpeg -y -vstats -i in.mp4 -c:v libx264 out.mp4")
+		require.NoError(t, err)
+
+		b := w.(*writeCtx).b
+		assert.Equal(t, []string{"-y", "-vstats"}, b.global)
+		require.Len(t, b.inputs, 1)
+		assert.Empty(t, b.inputs[0].args)
+		assert.Equal(t, "in.mp4", b.inputs[0].path)
+		require.Len(t, b.outputs, 1)
+		assert.Equal(t, []string{"-c:v", "libx264"}, b.outputs[0].args)
+		assert.Equal(t, "out.mp4", b.outputs[0].path)
+
+		w, err = ParseArgs([]string{"-i", "in.mp4", "-fake_bool", "-an", "-stream_loop", "-1", "-itsoffset", "-00:00:02.500", "out.mp4"})
+		require.NoError(t, err)
+		b = w.(*writeCtx).b
+		require.Len(t, b.inputs, 1)
+		assert.Equal(t, []string{"-fake_bool", "-an", "-stream_loop", "-1", "-itsoffset", "-00:00:02.500"}, b.outputs[0].args)
+		assert.Equal(t, "out.mp4", b.outputs[0].path)
+	})
+
+	t.Run("Último token é sempre o arquivo de saída", func(t *testing.T) {
+		w, err := Parse("ffmpeg -i a.mp4 -seek_timestamp out.mp4")
+		require.NoError(t, err)
+		b := w.(*writeCtx).b
+		require.Len(t, b.outputs, 1)
+		assert.Equal(t, []string{"-seek_timestamp"}, b.outputs[0].args)
+		assert.Equal(t, "out.mp4", b.outputs[0].path)
+
+		w, err = Parse("ffmpeg -i a.mp4 -fake_bool out.mp4")
+		require.NoError(t, err)
+		b = w.(*writeCtx).b
+		require.Len(t, b.outputs, 1)
+		assert.Equal(t, []string{"-fake_bool"}, b.outputs[0].args)
+		assert.Equal(t, "out.mp4", b.outputs[0].path)
+
+		w, err = Parse("ffmpeg -i a.mp4 out.mp4 -loglevel error")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"-loglevel", "error"}, w.(*writeCtx).b.global)
+	})
+
+	t.Run("Desfaz o escape do graph", func(t *testing.T) {
+		w, err := Parse(`ffmpeg -i in.mp4 -vf "drawtext=text='a, b':fontsize=12,hflip" out.mp4`)
+		require.NoError(t, err)
+
+		chain := w.(*writeCtx).b.outputs[0].chain(FilterVideo)
+		assert.Equal(t, []AtomicFilter{
+			{Name: "drawtext", Params: []string{"text=a, b", "fontsize=12"}},
+			{Name: "hflip"},
+		}, chain.filters)
+		assert.Equal(t, `ffmpeg -i in.mp4 -vf drawtext=text=a\, b:fontsize=12,hflip out.mp4`, w.Build())
+	})
+
+	t.Run("Mantém graphs não representáveis como Raw", func(t *testing.T) {
+		args := []string{"-i", "in.mp4", "-vf", "split[a][b];[a][b]hstack", "out.mp4"}
+		w, err := ParseArgs(args)
+		require.NoError(t, err)
+		assert.Equal(t, args, w.Args())
+		assert.Empty(t, w.(*writeCtx).b.outputs[0].filters)
+	})
+
+	t.Run("Mantém filter_complex não representável na posição original", func(t *testing.T) {
+		args := []string{
+			"-i", "a.mp4", "-i", "b.mp4",
+			"-filter_complex", "[0:v]scale=320:-1,[1:v]overlay[out]",
+			"-map", "[out]", "out.mp4",
+		}
+		w, err := ParseArgs(args)
+		require.NoError(t, err)
+		assert.Equal(t, args, w.Args())
+		assert.Empty(t, w.(*writeCtx).b.global)
+	})
+
+	t.Run("Erros", func(t *testing.T) {
+		_, err := ParseArgs([]string{"-i", "in.mp4", "out.mp4", "-an", "-sn"})
+		assert.ErrorContains(t, err, "trailing options without an output file: -an -sn")
+
+		_, err = ParseArgs([]string{"-i"})
+		assert.ErrorContains(t, err, "flag -i expects a value")
+
+		_, err = Parse(`ffmpeg -i 'in.mp4`)
+		assert.Error(t, err)
+	})
+}
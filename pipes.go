@@ -0,0 +1,157 @@
+package fflow
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"sync"
+	"syscall"
+)
+
+// pipeStream é um pipe extra (pipe:3 em diante) entre o processo atual e o ffmpeg.
+// Apenas um dos campos é definido.
+//
+// pipeStream is an extra pipe (pipe:3 onwards) between the current process and ffmpeg.
+// Only one of the fields is set.
+type pipeStream struct {
+	reader io.Reader
+	writer io.Writer
+}
+
+// addReader registra r como fonte de um input e retorna o caminho usado no -i:
+// pipe:0 (stdin) para o primeiro reader e pipe:3, pipe:4... para os seguintes.
+//
+// addReader registers r as the source of an input and returns the path used in -i:
+// pipe:0 (stdin) for the first reader and pipe:3, pipe:4... for the next ones.
+func (b *ffmpegBuilder) addReader(r io.Reader) string {
+	if b.stdin == nil {
+		b.stdin = r
+		return "pipe:0"
+	}
+	b.extraPipes = append(b.extraPipes, pipeStream{reader: r})
+	return fmt.Sprintf("pipe:%d", 2+len(b.extraPipes))
+}
+
+// addWriter registra w como destino de um output e retorna o caminho do output:
+// pipe:1 (stdout) para o primeiro writer e pipe:3, pipe:4... para os seguintes.
+//
+// addWriter registers w as the destination of an output and returns the output path:
+// pipe:1 (stdout) for the first writer and pipe:3, pipe:4... for the next ones.
+func (b *ffmpegBuilder) addWriter(w io.Writer) string {
+	if b.stdout == nil {
+		b.stdout = w
+		return "pipe:1"
+	}
+	b.extraPipes = append(b.extraPipes, pipeStream{writer: w})
+	return fmt.Sprintf("pipe:%d", 2+len(b.extraPipes))
+}
+
+// pipes liga os pipes extras ao processo do ffmpeg: child são as pontas passadas
+// via ExtraFiles e parent as pontas usadas para copiar de/para os readers e writers.
+//
+// pipes connects the extra pipes to the ffmpeg process: child are the ends passed
+// via ExtraFiles and parent the ends used to copy from/to the readers and writers.
+type pipes struct {
+	streams []pipeStream
+	child   []*os.File
+	parent  []*os.File
+
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+func (b *ffmpegBuilder) openPipes() (*pipes, error) {
+	p := &pipes{streams: b.extraPipes}
+	for _, s := range b.extraPipes {
+		r, w, err := os.Pipe()
+		if err != nil {
+			p.close()
+			return nil, err
+		}
+		if s.reader != nil {
+			p.child = append(p.child, r)
+			p.parent = append(p.parent, w)
+		} else {
+			p.child = append(p.child, w)
+			p.parent = append(p.parent, r)
+		}
+	}
+	return p, nil
+}
+
+// start fecha as pontas do ffmpeg no processo atual, depois que ele as herdou,
+// e começa a copiar os dados.
+//
+// start closes ffmpeg's ends in the current process, after it inherited them,
+// and starts copying the data.
+func (p *pipes) start() {
+	for _, f := range p.child {
+		_ = f.Close()
+	}
+	for i, s := range p.streams {
+		p.wg.Add(1)
+		go func(s pipeStream, f *os.File) {
+			defer p.wg.Done()
+			defer f.Close()
+
+			var err error
+			if s.reader != nil {
+				_, err = io.Copy(f, s.reader)
+				// INFO: o ffmpeg pode terminar sem ler o input inteiro.
+				if errors.Is(err, syscall.EPIPE) {
+					err = nil
+				}
+			} else {
+				_, err = io.Copy(s.writer, f)
+			}
+			if err != nil {
+				p.mu.Lock()
+				p.errs = append(p.errs, fmt.Errorf("pipe:%d: %w", 3+i, err))
+				p.mu.Unlock()
+			}
+		}(s, p.parent[i])
+	}
+}
+
+// wait aguarda o fim das cópias e retorna os erros de leitura ou escrita.
+//
+// wait waits for the copies to finish and returns the read or write errors.
+func (p *pipes) wait() error {
+	p.wg.Wait()
+	return errors.Join(p.errs...)
+}
+
+// close fecha todas as pontas; usado quando o ffmpeg não chegou a iniciar.
+//
+// close closes every end; used when ffmpeg did not start.
+func (p *pipes) close() {
+	for _, f := range append(p.child, p.parent...) {
+		_ = f.Close()
+	}
+}
+
+// progressPipe cria o pipe dedicado ao -progress, herdado pelo ffmpeg depois dos pipes
+// extras, e acrescenta "-progress pipe:N -nostats" a spec. No Windows, ou quando
+// extraFiles é false (ver ExtraFilesSupporter), o progresso continua no stderr (pipe:2)
+// e r e w são nil.
+//
+// progressPipe creates the pipe dedicated to -progress, inherited by ffmpeg after the
+// extra pipes, and appends "-progress pipe:N -nostats" to spec. On Windows, or when
+// extraFiles is false (see ExtraFilesSupporter), progress stays on stderr (pipe:2)
+// and r and w are nil.
+func progressPipe(spec *ExecSpec, extraFiles bool) (r, w *os.File, err error) {
+	target := "pipe:2"
+	if extraFiles && runtime.GOOS != "windows" {
+		if r, w, err = os.Pipe(); err != nil {
+			return nil, nil, err
+		}
+		target = fmt.Sprintf("pipe:%d", 3+len(spec.ExtraFiles))
+		spec.ExtraFiles = append(slices.Clone(spec.ExtraFiles), w)
+	}
+	spec.Args = append(spec.Args, "-progress", target, "-nostats")
+	return r, w, nil
+}
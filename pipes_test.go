@@ -0,0 +1,142 @@
+package fflow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain permite que o binário de teste faça o papel do ffmpeg nos testes de pipes e de cancelamento.
+// Os binários padrão são fixados para que FFMPEG_BIN e FFPROBE_BIN não alterem os comandos esperados.
+func TestMain(m *testing.M) {
+	DefaultBinary = "ffmpeg"
+	DefaultProbeBinary = "ffprobe"
+
+	switch os.Getenv("FFLOW_FAKE_FFMPEG") {
+	case "pipes":
+		fakeFFmpeg()
+		return
+	case "":
+	default:
+		fakeCancel(os.Getenv("FFLOW_FAKE_FFMPEG"))
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// fakeFFmpeg lê os inputs de pipe:0 e pipe:3 e escreve os outputs em pipe:1 e pipe:4.
+// Com -progress pipe:N, escreve um bloco de progresso em N e uma linha de log no stderr.
+func fakeFFmpeg() {
+	video, _ := io.ReadAll(os.Stdin)
+	audio, _ := io.ReadAll(os.NewFile(3, "pipe:3"))
+
+	_, _ = os.Stdout.WriteString("video:" + string(video))
+	out := os.NewFile(4, "pipe:4")
+	_, _ = out.WriteString("audio:" + string(audio))
+	_ = out.Close()
+
+	if i := slices.Index(os.Args, "-progress"); i >= 0 {
+		var fd uintptr
+		fmt.Sscanf(os.Args[i+1], "pipe:%d", &fd)
+		progress := os.NewFile(fd, os.Args[i+1])
+		_, _ = progress.WriteString("frame=" + strconv.Itoa(len(video)) + "\nprogress=end\n")
+		_ = progress.Close()
+		_, _ = os.Stderr.WriteString("[fake @ 0x1] done\n")
+	}
+}
+
+func TestPipes(t *testing.T) {
+	t.Run("Argumentos", func(t *testing.T) {
+		var out1, out2 bytes.Buffer
+		w := New().
+			InputReader(strings.NewReader(""), "matroska").
+			InputReader(strings.NewReader(""), "", InputRe()).
+			Input("logo.png").
+			Output("file.mp4").
+			NextOutput().
+			Map("0:v").
+			OutputWriter(&out1, "mpegts").
+			NextOutput().
+			Map("1:a").
+			OutputWriter(&out2, "adts")
+
+		assert.Equal(t, "-loglevel error -y -f matroska -i pipe:0 -re -i pipe:3 -i logo.png "+
+			"file.mp4 -map 0:v -f mpegts pipe:1 -map 1:a -f adts pipe:4", w.String())
+	})
+
+	t.Run("Run liga readers e writers ao processo", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("ExtraFiles não é suportado no Windows")
+		}
+
+		var video, audio bytes.Buffer
+		err := New(WithBinary(os.Args[0]), WithEnv("FFLOW_FAKE_FFMPEG=pipes")).
+			InputReader(strings.NewReader("frames"), "h264").
+			InputReader(strings.NewReader("samples"), "s16le").
+			OutputWriter(&video, "h264").
+			NextOutput().
+			OutputWriter(&audio, "s16le").
+			Command().
+			Run(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, "video:frames", video.String())
+		assert.Equal(t, "audio:samples", audio.String())
+	})
+
+	t.Run("RunWithProgress liga readers e writers ao processo", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("ExtraFiles não é suportado no Windows")
+		}
+
+		var video, audio bytes.Buffer
+		var logs []LogLine
+		pch, ech := New(WithBinary(os.Args[0]), WithEnv("FFLOW_FAKE_FFMPEG=pipes"),
+			WithLogFunc(func(l LogLine) { logs = append(logs, l) })).
+			InputReader(strings.NewReader("frames"), "h264").
+			InputReader(strings.NewReader("samples"), "s16le").
+			OutputWriter(&video, "h264").
+			NextOutput().
+			OutputWriter(&audio, "s16le").
+			Command().
+			RunWithProgress(context.Background())
+
+		var last Progress
+		for p := range pch {
+			last = p
+		}
+		require.NoError(t, <-ech)
+		assert.Equal(t, "video:frames", video.String())
+		assert.Equal(t, "audio:samples", audio.String())
+		assert.Equal(t, len("frames"), last.Frame)
+		assert.Equal(t, []LogLine{{Component: "fake @ 0x1", Message: "done", Raw: "[fake @ 0x1] done"}}, logs)
+	})
+
+	t.Run("Cmd usa stdin e stdout", func(t *testing.T) {
+		var out bytes.Buffer
+		in := strings.NewReader("frames")
+		cmd := New(WithBinary(os.Args[0])).InputReader(in, "h264").OutputWriter(&out, "h264").Command().Cmd(context.Background())
+
+		assert.Equal(t, in, cmd.Stdin)
+		assert.Equal(t, &out, cmd.Stdout)
+		assert.NoError(t, cmd.Err)
+
+		cmd = New(WithBinary(os.Args[0])).
+			InputReader(strings.NewReader(""), "h264").
+			InputReader(strings.NewReader(""), "h264").
+			Output("out.mp4").
+			Command().
+			Cmd(context.Background())
+		assert.ErrorContains(t, cmd.Err, "does not support extra pipes")
+	})
+}
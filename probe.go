@@ -0,0 +1,365 @@
+package fflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MediaInfo reúne as informações retornadas pelo ffprobe para um arquivo de mídia.
+//
+// MediaInfo gathers the information returned by ffprobe for a media file.
+type MediaInfo struct {
+	Format   FormatInfo
+	Streams  []StreamInfo
+	Chapters []ChapterInfo
+	Frames   []FrameInfo
+}
+
+// FormatInfo descreve o container do arquivo (-show_format).
+//
+// FormatInfo describes the file container (-show_format).
+type FormatInfo struct {
+	Filename       string
+	FormatName     string
+	FormatLongName string
+	NbStreams      int
+	StartTime      time.Duration
+	Duration       time.Duration
+	Size           int64
+	BitRate        int64
+	Tags           map[string]string
+}
+
+// StreamInfo descreve um stream do arquivo (-show_streams).
+//
+// StreamInfo describes a stream of the file (-show_streams).
+type StreamInfo struct {
+	Index         int
+	Type          StreamType
+	CodecName     string
+	CodecLongName string
+	Profile       string
+	Width         int
+	Height        int
+	PixFmt        string
+	FrameRate     float64
+	SampleRate    int
+	Channels      int
+	ChannelLayout string
+	Duration      time.Duration
+	BitRate       int64
+	NbFrames      int64
+	Tags          map[string]string
+}
+
+// ChapterInfo descreve um capítulo do arquivo (-show_chapters).
+//
+// ChapterInfo describes a chapter of the file (-show_chapters).
+type ChapterInfo struct {
+	ID        int64
+	StartTime time.Duration
+	EndTime   time.Duration
+	Tags      map[string]string
+}
+
+// FrameInfo descreve um frame decodificado (-show_frames).
+//
+// FrameInfo describes a decoded frame (-show_frames).
+type FrameInfo struct {
+	Type        StreamType
+	StreamIndex int
+	KeyFrame    bool
+	PTSTime     time.Duration
+	Duration    time.Duration
+	PictType    string
+	Width       int
+	Height      int
+}
+
+// StreamsOf retorna apenas os streams do tipo informado.
+//
+// StreamsOf returns only the streams of the given type.
+func (m *MediaInfo) StreamsOf(t StreamType) []StreamInfo {
+	var streams []StreamInfo
+	for _, s := range m.Streams {
+		if s.Type == t {
+			streams = append(streams, s)
+		}
+	}
+	return streams
+}
+
+// ProbeOption configura quais seções o ffprobe deve retornar.
+//
+// ProbeOption configures which sections ffprobe should return.
+type ProbeOption func(*probeConfig)
+
+type probeConfig struct {
+	chapters bool
+	frames   bool
+	binary   string
+	options  []Option
+}
+
+// DefaultProbeBinary é o binário do ffprobe usado por Probe sem WithProbeBinary.
+// É inicializado a partir da variável de ambiente FFPROBE_BIN, ou "ffprobe" quando ela não está definida.
+//
+// DefaultProbeBinary is the ffprobe binary used by Probe without WithProbeBinary.
+// It is initialized from the FFPROBE_BIN environment variable, or "ffprobe" when it is unset.
+var DefaultProbeBinary = defaultProbeBinary()
+
+// WithProbeBinary define o caminho do binário do ffprobe usado por Probe.
+//
+// WithProbeBinary sets the path of the ffprobe binary used by Probe.
+func WithProbeBinary(path string) ProbeOption {
+	return func(c *probeConfig) { c.binary = path }
+}
+
+// ProbeWith aplica as opções do builder (WithExecutor, WithEnv, WithDir, WithStderrLines)
+// ao processo do ffprobe. WithBinary é ignorado; use WithProbeBinary.
+//
+// ProbeWith applies the builder options (WithExecutor, WithEnv, WithDir, WithStderrLines)
+// to the ffprobe process. WithBinary is ignored; use WithProbeBinary.
+func ProbeWith(opts ...Option) ProbeOption {
+	return func(c *probeConfig) { c.options = append(c.options, opts...) }
+}
+
+// ProbeChapters inclui os capítulos do arquivo (-show_chapters).
+//
+// ProbeChapters includes the file chapters (-show_chapters).
+func ProbeChapters() ProbeOption {
+	return func(c *probeConfig) { c.chapters = true }
+}
+
+// ProbeFrames inclui todos os frames do arquivo (-show_frames).
+// Pode ser lento em arquivos grandes.
+//
+// ProbeFrames includes every frame of the file (-show_frames).
+// It can be slow on large files.
+func ProbeFrames() ProbeOption {
+	return func(c *probeConfig) { c.frames = true }
+}
+
+// Probe executa o ffprobe sobre path e retorna as informações de formato e streams.
+// O processo é iniciado pelo Executor configurado com ProbeWith; falhas são
+// retornadas como *FFmpegError.
+//
+// Probe runs ffprobe on path and returns its format and stream information.
+// The process is started by the Executor configured with ProbeWith; failures are
+// returned as *FFmpegError.
+func Probe(ctx context.Context, path string, opts ...ProbeOption) (*MediaInfo, error) {
+	cfg := newProbeConfig(opts...)
+	b := newBuilder(nil, cfg.options...)
+	b.binary = cfg.binary
+
+	out, err := b.output(ctx, cfg.args(path)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseProbe([]byte(out))
+}
+
+func newProbeConfig(opts ...ProbeOption) probeConfig {
+	cfg := probeConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.binary == "" {
+		cfg.binary = DefaultProbeBinary
+	}
+	return cfg
+}
+
+func probeArgs(path string, opts ...ProbeOption) []string {
+	cfg := newProbeConfig(opts...)
+	return cfg.args(path)
+}
+
+func (c probeConfig) args(path string) []string {
+	args := []string{"-v", "error", "-print_format", "json", "-show_format", "-show_streams"}
+	if c.chapters {
+		args = append(args, "-show_chapters")
+	}
+	if c.frames {
+		args = append(args, "-show_frames")
+	}
+	return append(args, path)
+}
+
+func defaultProbeBinary() string {
+	if bin := os.Getenv("FFPROBE_BIN"); bin != "" {
+		return bin
+	}
+	return "ffprobe"
+}
+
+type probeOutput struct {
+	Format struct {
+		Filename       string            `json:"filename"`
+		NbStreams      int               `json:"nb_streams"`
+		FormatName     string            `json:"format_name"`
+		FormatLongName string            `json:"format_long_name"`
+		StartTime      string            `json:"start_time"`
+		Duration       string            `json:"duration"`
+		Size           string            `json:"size"`
+		BitRate        string            `json:"bit_rate"`
+		Tags           map[string]string `json:"tags"`
+	} `json:"format"`
+	Streams []struct {
+		Index         int               `json:"index"`
+		CodecName     string            `json:"codec_name"`
+		CodecLongName string            `json:"codec_long_name"`
+		Profile       string            `json:"profile"`
+		CodecType     string            `json:"codec_type"`
+		Width         int               `json:"width"`
+		Height        int               `json:"height"`
+		PixFmt        string            `json:"pix_fmt"`
+		AvgFrameRate  string            `json:"avg_frame_rate"`
+		SampleRate    string            `json:"sample_rate"`
+		Channels      int               `json:"channels"`
+		ChannelLayout string            `json:"channel_layout"`
+		Duration      string            `json:"duration"`
+		BitRate       string            `json:"bit_rate"`
+		NbFrames      string            `json:"nb_frames"`
+		Tags          map[string]string `json:"tags"`
+	} `json:"streams"`
+	Chapters []struct {
+		ID        int64             `json:"id"`
+		StartTime string            `json:"start_time"`
+		EndTime   string            `json:"end_time"`
+		Tags      map[string]string `json:"tags"`
+	} `json:"chapters"`
+	Frames []struct {
+		MediaType   string `json:"media_type"`
+		StreamIndex int    `json:"stream_index"`
+		KeyFrame    int    `json:"key_frame"`
+		PTSTime     string `json:"pts_time"`
+		Duration    string `json:"duration_time"`
+		PictType    string `json:"pict_type"`
+		Width       int    `json:"width"`
+		Height      int    `json:"height"`
+	} `json:"frames"`
+}
+
+func parseProbe(data []byte) (*MediaInfo, error) {
+	var out probeOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("ffprobe output: %w", err)
+	}
+
+	info := &MediaInfo{
+		Format: FormatInfo{
+			Filename:       out.Format.Filename,
+			FormatName:     out.Format.FormatName,
+			FormatLongName: out.Format.FormatLongName,
+			NbStreams:      out.Format.NbStreams,
+			StartTime:      parseSeconds(out.Format.StartTime),
+			Duration:       parseSeconds(out.Format.Duration),
+			Size:           parseInt(out.Format.Size),
+			BitRate:        parseInt(out.Format.BitRate),
+			Tags:           out.Format.Tags,
+		},
+	}
+
+	for _, s := range out.Streams {
+		info.Streams = append(info.Streams, StreamInfo{
+			Index:         s.Index,
+			Type:          streamTypeOf(s.CodecType),
+			CodecName:     s.CodecName,
+			CodecLongName: s.CodecLongName,
+			Profile:       s.Profile,
+			Width:         s.Width,
+			Height:        s.Height,
+			PixFmt:        s.PixFmt,
+			FrameRate:     parseRate(s.AvgFrameRate),
+			SampleRate:    int(parseInt(s.SampleRate)),
+			Channels:      s.Channels,
+			ChannelLayout: s.ChannelLayout,
+			Duration:      parseSeconds(s.Duration),
+			BitRate:       parseInt(s.BitRate),
+			NbFrames:      parseInt(s.NbFrames),
+			Tags:          s.Tags,
+		})
+	}
+
+	for _, c := range out.Chapters {
+		info.Chapters = append(info.Chapters, ChapterInfo{
+			ID:        c.ID,
+			StartTime: parseSeconds(c.StartTime),
+			EndTime:   parseSeconds(c.EndTime),
+			Tags:      c.Tags,
+		})
+	}
+
+	for _, f := range out.Frames {
+		info.Frames = append(info.Frames, FrameInfo{
+			Type:        streamTypeOf(f.MediaType),
+			StreamIndex: f.StreamIndex,
+			KeyFrame:    f.KeyFrame == 1,
+			PTSTime:     parseSeconds(f.PTSTime),
+			Duration:    parseSeconds(f.Duration),
+			PictType:    f.PictType,
+			Width:       f.Width,
+			Height:      f.Height,
+		})
+	}
+
+	return info, nil
+}
+
+// streamTypeOf converte o codec_type do ffprobe para StreamType.
+//
+// streamTypeOf converts an ffprobe codec_type into a StreamType.
+func streamTypeOf(codecType string) StreamType {
+	switch codecType {
+	case "video":
+		return Video
+	case "audio":
+		return Audio
+	case "subtitle":
+		return Subtitle
+	case "data":
+		return Data
+	case "attachment":
+		return Attachment
+	}
+	return StreamType(codecType)
+}
+
+func parseSeconds(s string) time.Duration {
+	sec, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(sec * float64(time.Second))
+}
+
+func parseInt(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
+
+// parseRate converte frações como "30000/1001" em float.
+//
+// parseRate converts fractions such as "30000/1001" into a float.
+func parseRate(s string) float64 {
+	num, den, found := strings.Cut(s, "/")
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	if !found {
+		return n
+	}
+	d, err := strconv.ParseFloat(den, 64)
+	if err != nil || d == 0 {
+		return 0
+	}
+	return n / d
+}
@@ -0,0 +1,139 @@
+package fflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const probeJSON = `{
+  "streams": [
+    {
+      "index": 0,
+      "codec_name": "h264",
+      "codec_long_name": "H.264 / AVC / MPEG-4 AVC / MPEG-4 part 10",
+      "profile": "High",
+      "codec_type": "video",
+      "width": 1920,
+      "height": 1080,
+      "pix_fmt": "yuv420p",
+      "avg_frame_rate": "30000/1001",
+      "duration": "12.512000",
+      "bit_rate": "4500000",
+      "nb_frames": "375",
+      "tags": {"language": "und"}
+    },
+    {
+      "index": 1,
+      "codec_name": "aac",
+      "codec_type": "audio",
+      "sample_rate": "48000",
+      "channels": 2,
+      "channel_layout": "stereo",
+      "duration": "12.480000",
+      "bit_rate": "128000"
+    }
+  ],
+  "chapters": [
+    {"id": 0, "start_time": "0.000000", "end_time": "6.000000", "tags": {"title": "Intro"}}
+  ],
+  "frames": [
+    {"media_type": "video", "stream_index": 0, "key_frame": 1, "pts_time": "0.033367", "pict_type": "I", "width": 1920, "height": 1080}
+  ],
+  "format": {
+    "filename": "movie.mp4",
+    "nb_streams": 2,
+    "format_name": "mov,mp4,m4a,3gp,3g2,mj2",
+    "start_time": "0.000000",
+    "duration": "12.512000",
+    "size": "7340032",
+    "bit_rate": "4693181",
+    "tags": {"encoder": "Lavf60.3.100"}
+  }
+}`
+
+func TestProbe(t *testing.T) {
+	t.Run("Argumentos do ffprobe", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"-v", "error", "-print_format", "json", "-show_format", "-show_streams", "movie.mp4"},
+			probeArgs("movie.mp4"),
+		)
+		assert.Equal(t,
+			[]string{"-v", "error", "-print_format", "json", "-show_format", "-show_streams", "-show_chapters", "-show_frames", "movie.mp4"},
+			probeArgs("movie.mp4", ProbeChapters(), ProbeFrames()),
+		)
+	})
+
+	t.Run("Converte o JSON em MediaInfo", func(t *testing.T) {
+		info, err := parseProbe([]byte(probeJSON))
+		require.NoError(t, err)
+
+		assert.Equal(t, "movie.mp4", info.Format.Filename)
+		assert.Equal(t, 2, info.Format.NbStreams)
+		assert.Equal(t, 12512*time.Millisecond, info.Format.Duration)
+		assert.Equal(t, int64(7340032), info.Format.Size)
+		assert.Equal(t, "Lavf60.3.100", info.Format.Tags["encoder"])
+
+		require.Len(t, info.Streams, 2)
+		video := info.Streams[0]
+		assert.Equal(t, Video, video.Type)
+		assert.Equal(t, "h264", video.CodecName)
+		assert.Equal(t, 1920, video.Width)
+		assert.InDelta(t, 29.97, video.FrameRate, 0.01)
+		assert.Equal(t, int64(375), video.NbFrames)
+
+		audio := info.Streams[1]
+		assert.Equal(t, Audio, audio.Type)
+		assert.Equal(t, 48000, audio.SampleRate)
+		assert.Equal(t, "stereo", audio.ChannelLayout)
+
+		require.Len(t, info.Chapters, 1)
+		assert.Equal(t, 6*time.Second, info.Chapters[0].EndTime)
+		assert.Equal(t, "Intro", info.Chapters[0].Tags["title"])
+
+		require.Len(t, info.Frames, 1)
+		assert.True(t, info.Frames[0].KeyFrame)
+		assert.Equal(t, "I", info.Frames[0].PictType)
+	})
+
+	t.Run("StreamsOf filtra pelo tipo", func(t *testing.T) {
+		info, err := parseProbe([]byte(probeJSON))
+		require.NoError(t, err)
+
+		assert.Len(t, info.StreamsOf(Audio), 1)
+		assert.Empty(t, info.StreamsOf(Subtitle))
+	})
+
+	t.Run("Probe usa o Executor e o binário configurados", func(t *testing.T) {
+		fake := &fakeExecutor{stdout: probeJSON}
+		info, err := Probe(context.Background(), "movie.mp4",
+			WithProbeBinary("/opt/ffmpeg/bin/ffprobe"),
+			ProbeWith(WithExecutor(fake), WithEnv("LANG=C"), WithDir("/media")))
+
+		require.NoError(t, err)
+		assert.Equal(t, "/opt/ffmpeg/bin/ffprobe", fake.spec.Path)
+		assert.Equal(t, probeArgs("movie.mp4"), fake.spec.Args)
+		assert.Equal(t, []string{"LANG=C"}, fake.spec.Env)
+		assert.Equal(t, "/media", fake.spec.Dir)
+		assert.Len(t, info.Streams, 2)
+	})
+
+	t.Run("Probe retorna *FFmpegError", func(t *testing.T) {
+		fake := &fakeExecutor{stderr: "missing.mp4: No such file or directory\n", err: fakeExitError{code: 1}}
+		_, err := Probe(context.Background(), "missing.mp4", ProbeWith(WithExecutor(fake)))
+
+		var ffErr *FFmpegError
+		require.True(t, errors.As(err, &ffErr))
+		assert.Equal(t, "ffprobe", ffErr.Args[0])
+		assert.Equal(t, KindInputNotFound, ffErr.Kind)
+	})
+
+	t.Run("JSON inválido", func(t *testing.T) {
+		_, err := parseProbe([]byte("not json"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,150 @@
+package fflow
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Progress representa um evento de progresso emitido pelo ffmpeg (-progress).
+//
+// Progress represents a progress event emitted by ffmpeg (-progress).
+type Progress struct {
+	Frame   int
+	FPS     float64
+	Bitrate string
+	OutTime time.Duration
+	Speed   string
+
+	// TotalSize é o tamanho do output já escrito, em bytes.
+	//
+	// TotalSize is the size of the output written so far, in bytes.
+	TotalSize  int64
+	DupFrames  int
+	DropFrames int
+
+	// Quantizers mapeia "arquivo:stream" do output para o quantizador atual (stream_0_0_q).
+	//
+	// Quantizers maps the output "file:stream" to its current quantizer (stream_0_0_q).
+	Quantizers map[string]float64
+
+	// SpeedFactor é o valor numérico de Speed ("1.5x" -> 1.5), ou 0 quando indisponível.
+	//
+	// SpeedFactor is the numeric value of Speed ("1.5x" -> 1.5), or 0 when unavailable.
+	SpeedFactor float64
+
+	// Percent e ETA só são preenchidos quando a duração total do output é conhecida,
+	// via WithDuration ou pelas flags -t/-to/-ss do builder.
+	//
+	// Percent and ETA are only filled when the total output duration is known,
+	// through WithDuration or the builder -t/-to/-ss flags.
+	Percent float64
+	ETA     time.Duration
+}
+
+// WithDuration informa a duração da mídia de entrada (por exemplo, FormatInfo.Duration
+// obtido com Probe), usada para calcular Percent e ETA em RunWithProgress.
+//
+// WithDuration sets the input media duration (for example, FormatInfo.Duration
+// obtained with Probe), used to compute Percent and ETA in RunWithProgress.
+func WithDuration(d time.Duration) Option {
+	return func(b *ffmpegBuilder) { b.duration = d }
+}
+
+func (p *Progress) estimate(total time.Duration) {
+	if total <= 0 {
+		return
+	}
+
+	p.Percent = min(float64(p.OutTime)/float64(total)*100, 100)
+	p.ETA = 0
+	if remaining := total - p.OutTime; remaining > 0 && p.SpeedFactor > 0 {
+		p.ETA = time.Duration(float64(remaining) / p.SpeedFactor)
+	}
+}
+
+// quantizerKey converte chaves como "stream_0_1_q" em "0:1".
+//
+// quantizerKey converts keys such as "stream_0_1_q" into "0:1".
+func quantizerKey(key string) (string, bool) {
+	if !strings.HasPrefix(key, "stream_") || !strings.HasSuffix(key, "_q") {
+		return "", false
+	}
+	ref := strings.TrimSuffix(strings.TrimPrefix(key, "stream_"), "_q")
+	return strings.ReplaceAll(ref, "_", ":"), true
+}
+
+func parseSpeed(speed string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(speed), "x"), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// span guarda os limites de tempo (-ss, -t, -to) de um conjunto de argumentos.
+//
+// span holds the time limits (-ss, -t, -to) of a set of arguments.
+type span struct {
+	ss, t, to time.Duration
+}
+
+func spanOf(args []string) span {
+	var s span
+	for i := 0; i+1 < len(args); i++ {
+		d, ok := parseDuration(args[i+1])
+		if !ok {
+			continue
+		}
+
+		switch args[i] {
+		case "-ss":
+			s.ss = d
+		case "-t":
+			s.t = d
+		case "-to":
+			s.to = d
+		default:
+			continue
+		}
+		i++
+	}
+	return s
+}
+
+// limit retorna a duração resultante de aplicar o span a uma mídia de duração total
+// (0 quando desconhecida).
+//
+// limit returns the duration resulting from applying the span to media of the given
+// total duration (0 when unknown).
+func (s span) limit(total time.Duration) time.Duration {
+	end := total
+	if s.to > 0 && (end <= 0 || s.to < end) {
+		end = s.to
+	}
+
+	var length time.Duration
+	if end > 0 {
+		length = end - s.ss
+	}
+	if s.t > 0 && (length <= 0 || s.t < length) {
+		length = s.t
+	}
+	return max(length, 0)
+}
+
+// expectedDuration estima a duração do primeiro output a partir de WithDuration
+// e dos limites de tempo do input e do output.
+//
+// expectedDuration estimates the duration of the first output from WithDuration
+// and the input and output time limits.
+func (b *ffmpegBuilder) expectedDuration() time.Duration {
+	var in, out span
+	if len(b.inputs) > 0 {
+		in = spanOf(b.inputs[0].args)
+	}
+	if len(b.outputs) > 0 {
+		out = spanOf(b.outputs[0].args)
+	}
+	return out.limit(in.limit(b.duration))
+}
@@ -0,0 +1,124 @@
+package fflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgress(t *testing.T) {
+	t.Run("Duração esperada do output", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			builder  writeStage
+			expected time.Duration
+		}{
+			{
+				name:     "Desconhecida",
+				builder:  New().Input("in.mp4").Output("out.mp4"),
+				expected: 0,
+			},
+			{
+				name:     "WithDuration",
+				builder:  New(WithDuration(time.Minute)).Input("in.mp4").Output("out.mp4"),
+				expected: time.Minute,
+			},
+			{
+				name:     "-t antes do -i",
+				builder:  New().T(30 * time.Second).Input("in.mp4").Output("out.mp4"),
+				expected: 30 * time.Second,
+			},
+			{
+				name:     "-ss e -to depois do -i",
+				builder:  New().Input("in.mp4").Ss(10 * time.Second).To(25 * time.Second).Output("out.mp4"),
+				expected: 15 * time.Second,
+			},
+			{
+				name:     "Seek no input sobre duração conhecida",
+				builder:  New(WithDuration(time.Minute)).Ss(20 * time.Second).Input("in.mp4").Output("out.mp4"),
+				expected: 40 * time.Second,
+			},
+			{
+				name:     "-t no output menor que o input",
+				builder:  New(WithDuration(time.Minute)).Input("in.mp4").T(5 * time.Second).Output("out.mp4"),
+				expected: 5 * time.Second,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				b := tt.builder.(*writeCtx).b
+				assert.Equal(t, tt.expected, b.expectedDuration())
+			})
+		}
+	})
+
+	t.Run("Percent, ETA e SpeedFactor", func(t *testing.T) {
+		fake := &fakeExecutor{progress: "out_time=00:00:05.000000\nspeed=2.5x\nprogress=continue\n" +
+			"out_time=00:00:10.000000\nspeed=N/A\nprogress=end\n"}
+		var events []Progress
+		err := New(WithExecutor(fake)).
+			Input("in.mp4").
+			T(10*time.Second).
+			Output("out.mp4").
+			Command().
+			RunWithProgressFunc(context.Background(), func(p Progress) { events = append(events, p) })
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+
+		assert.Equal(t, 2.5, events[0].SpeedFactor)
+		assert.Equal(t, 50.0, events[0].Percent)
+		assert.Equal(t, 2*time.Second, events[0].ETA)
+
+		assert.Equal(t, 0.0, events[1].SpeedFactor)
+		assert.Equal(t, 100.0, events[1].Percent)
+		assert.Equal(t, time.Duration(0), events[1].ETA)
+	})
+
+	t.Run("Chaves completas do -progress", func(t *testing.T) {
+		fake := &fakeExecutor{progress: "frame=120\nfps=59.94\nstream_0_0_q=28.0\nstream_1_0_q=-1.0\n" +
+			"bitrate=1200.5kbits/s\ntotal_size=1048576\nout_time_us=4004000\nout_time_ms=4004000\n" +
+			"out_time=00:00:04.004000\ndup_frames=3\ndrop_frames=7\nspeed=1.01x\nprogress=continue\n" +
+			"total_size=N/A\nprogress=end\n"}
+		var events []Progress
+		err := New(WithExecutor(fake)).
+			Input("in.mp4").
+			Output("out.mp4").
+			Command().
+			RunWithProgressFunc(context.Background(), func(p Progress) { events = append(events, p) })
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+
+		p := events[0]
+		assert.Equal(t, 120, p.Frame)
+		assert.Equal(t, int64(1048576), p.TotalSize)
+		assert.Equal(t, 4004*time.Millisecond, p.OutTime)
+		assert.Equal(t, 3, p.DupFrames)
+		assert.Equal(t, 7, p.DropFrames)
+		assert.Equal(t, map[string]float64{"0:0": 28, "1:0": -1}, p.Quantizers)
+
+		assert.Nil(t, events[1].Quantizers)
+		assert.Equal(t, int64(1048576), events[1].TotalSize)
+	})
+
+	t.Run("parseDuration", func(t *testing.T) {
+		tests := map[string]time.Duration{
+			"00:00:30.000":    30 * time.Second,
+			"01:02:03.500":    time.Hour + 2*time.Minute + 3500*time.Millisecond,
+			"02:05":           2*time.Minute + 5*time.Second,
+			"12.5":            12500 * time.Millisecond,
+			"00:00:01.500000": 1500 * time.Millisecond,
+		}
+		for in, expected := range tests {
+			d, ok := parseDuration(in)
+			assert.True(t, ok, in)
+			assert.Equal(t, expected, d, in)
+		}
+
+		_, ok := parseDuration("N/A")
+		assert.False(t, ok)
+	})
+}
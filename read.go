@@ -1,11 +1,17 @@
 package fflow
 
-import "time"
+import (
+	"io"
+	"strconv"
+	"time"
+)
 
 type readStagee interface {
-	// Ss adiciona a flag -ss após os inputs (-i), realizando um seek preciso no output.
+	// Ss adiciona a flag -ss após os inputs (-i), realizando um seek preciso no output atual.
+	// Para seek em um input específico, use InputSs.
 	//
-	// Ss adds the -ss flag after the inputs (-i), performing a precise seek on the output.
+	// Ss adds the -ss flag after the inputs (-i), performing a precise seek on the current output.
+	// To seek a specific input, use InputSs.
 	Ss(time.Duration) readStagee
 
 	// To adiciona a flag -to após os inputs (-i), definindo o tempo final absoluto do output.
@@ -18,10 +24,21 @@ type readStagee interface {
 	// T adds the -t flag after the inputs (-i), limiting the output duration.
 	T(time.Duration) readStagee
 
-	// Input adiciona um arquivo de entrada (-i).
+	// Input adiciona um arquivo de entrada (-i) com suas próprias opções.
+	// Exemplo: `.Input("logo.mov", InputSs(5*time.Second), InputStreamLoop(-1))`
 	//
-	// Input adds an input file (-i).
-	Input(path string) readStagee
+	// Input adds an input file (-i) with its own options.
+	// Example: `.Input("logo.mov", InputSs(5*time.Second), InputStreamLoop(-1))`
+	Input(path string, opts ...InputOption) readStagee
+
+	// InputReader adiciona um input lido de r. O primeiro reader usa o stdin (pipe:0);
+	// os seguintes usam pipes extras (pipe:3, pipe:4...), ligados pelo Run e RunWithProgress.
+	// format informa o demuxer (-f), já que o ffmpeg não consegue deduzi-lo pelo nome do arquivo.
+	//
+	// InputReader adds an input read from r. The first reader uses stdin (pipe:0);
+	// the next ones use extra pipes (pipe:3, pipe:4...), wired by Run and RunWithProgress.
+	// format sets the demuxer (-f), since ffmpeg cannot infer it from a file name.
+	InputReader(r io.Reader, format string, opts ...InputOption) readStagee
 
 	// Filter transiciona para a etapa de filtros da entrada atual.
 	//
@@ -32,27 +49,47 @@ type readStagee interface {
 	//
 	// Output sets the output file and transitions to WriteStage.
 	Output(path string) writeStage
+
+	// OutputWriter define um output escrito em w e transiciona para o WriteStage
+	// (ver writeStage.OutputWriter).
+	//
+	// OutputWriter sets an output written to w and transitions to WriteStage
+	// (see writeStage.OutputWriter).
+	OutputWriter(w io.Writer, format string) writeStage
+
+	// Clone retorna uma cópia independente deste estágio (ver beforeReadStage.Clone).
+	//
+	// Clone returns an independent copy of this stage (see beforeReadStage.Clone).
+	Clone() readStagee
 }
 
 type readCtx struct{ b *ffmpegBuilder }
 
 func (c *readCtx) T(d time.Duration) readStagee {
-	c.b.read = append(c.b.read, "-t", fmtDuration(d))
+	c.b.addOutputArgs("-t", fmtDuration(d))
 	return c
 }
 
 func (c *readCtx) Ss(d time.Duration) readStagee {
-	c.b.read = append(c.b.read, "-ss", fmtDuration(d))
+	c.b.addOutputArgs("-ss", fmtDuration(d))
 	return c
 }
 
 func (c *readCtx) To(d time.Duration) readStagee {
-	c.b.read = append(c.b.read, "-to", fmtDuration(d))
+	c.b.addOutputArgs("-to", fmtDuration(d))
+	return c
+}
+
+func (c *readCtx) Input(path string, opts ...InputOption) readStagee {
+	c.b.addInput(path, opts...)
 	return c
 }
 
-func (c *readCtx) Input(path string) readStagee {
-	c.b.read = append(c.b.read, "-i", path)
+func (c *readCtx) InputReader(r io.Reader, format string, opts ...InputOption) readStagee {
+	if format != "" {
+		opts = append([]InputOption{InputFormat(format)}, opts...)
+	}
+	c.b.addInput(c.b.addReader(r), opts...)
 	return c
 }
 
@@ -65,3 +102,90 @@ func (c *readCtx) Output(path string) writeStage {
 	write.Output(path)
 	return write
 }
+
+func (c *readCtx) OutputWriter(w io.Writer, format string) writeStage {
+	write := &writeCtx{c.b}
+	write.OutputWriter(w, format)
+	return write
+}
+
+func (c *readCtx) Clone() readStagee {
+	return &readCtx{c.b.clone()}
+}
+
+// InputOption configura um input específico; as opções são emitidas antes do seu -i,
+// na ordem em que foram informadas.
+//
+// InputOption configures a specific input; options are emitted before its -i,
+// in the order they were given.
+type InputOption func(*input)
+
+// InputSs realiza um seek rápido neste input (-ss).
+//
+// InputSs performs a fast seek on this input (-ss).
+func InputSs(d time.Duration) InputOption {
+	return InputRaw("-ss", fmtDuration(d))
+}
+
+// InputT limita quanto deste input será lido (-t).
+//
+// InputT limits how much of this input is read (-t).
+func InputT(d time.Duration) InputOption {
+	return InputRaw("-t", fmtDuration(d))
+}
+
+// InputTo define o tempo final absoluto da leitura deste input (-to).
+//
+// InputTo sets the absolute end time of this input read (-to).
+func InputTo(d time.Duration) InputOption {
+	return InputRaw("-to", fmtDuration(d))
+}
+
+// InputItsOffset desloca os timestamps deste input (-itsoffset).
+//
+// InputItsOffset shifts the timestamps of this input (-itsoffset).
+func InputItsOffset(d time.Duration) InputOption {
+	return InputRaw("-itsoffset", fmtDuration(d))
+}
+
+// InputFormat força o formato (demuxer) deste input (-f).
+//
+// InputFormat forces the format (demuxer) of this input (-f).
+func InputFormat(format string) InputOption {
+	return InputRaw("-f", format)
+}
+
+// InputRate força a taxa de quadros deste input (-r), ex.: "30" ou "30000/1001".
+//
+// InputRate forces the frame rate of this input (-r), e.g. "30" or "30000/1001".
+func InputRate(rate string) InputOption {
+	return InputRaw("-r", rate)
+}
+
+// InputStreamLoop repete este input n vezes (-stream_loop); -1 repete indefinidamente.
+//
+// InputStreamLoop loops this input n times (-stream_loop); -1 loops forever.
+func InputStreamLoop(n int) InputOption {
+	return InputRaw("-stream_loop", strconv.Itoa(n))
+}
+
+// InputRe lê este input na taxa nativa, como em transmissões ao vivo (-re).
+//
+// InputRe reads this input at its native rate, as in live streaming (-re).
+func InputRe() InputOption {
+	return InputRaw("-re")
+}
+
+// InputHWAccel define a aceleração de hardware usada para decodificar este input (-hwaccel).
+//
+// InputHWAccel sets the hardware acceleration used to decode this input (-hwaccel).
+func InputHWAccel(name string) InputOption {
+	return InputRaw("-hwaccel", name)
+}
+
+// InputRaw adiciona argumentos brutos antes do -i deste input.
+//
+// InputRaw adds raw arguments before the -i of this input.
+func InputRaw(values ...string) InputOption {
+	return func(in *input) { in.args = append(in.args, values...) }
+}
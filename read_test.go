@@ -45,6 +45,56 @@ func TestReadStage(t *testing.T) {
 		})
 	})
 
+	t.Run("Opções por input", func(t *testing.T) {
+		run(t, []testCase{
+			{
+				name: "Seek independente em cada input",
+				builder: New().
+					Input("main.mp4", InputSs(10*time.Second)).
+					Input("logo.mov", InputSs(2*time.Second), InputT(5*time.Second)).
+					Output("out.mp4"),
+				expected: "ffmpeg -loglevel error -y -ss 00:00:10.000 -i main.mp4 " +
+					"-ss 00:00:02.000 -t 00:00:05.000 -i logo.mov out.mp4",
+			},
+			{
+				name: "Opções do GlobalStage vão para o primeiro input",
+				builder: New().
+					Ss(3*time.Second).
+					Input("main.mp4").
+					Input("logo.png", InputStreamLoop(-1), InputRe()).
+					Output("out.mp4"),
+				expected: "ffmpeg -loglevel error -y -ss 00:00:03.000 -i main.mp4 -stream_loop -1 -re -i logo.png out.mp4",
+			},
+			{
+				name: "Formato, taxa, offset e hwaccel",
+				builder: New().
+					Input("cam.raw", InputFormat("rawvideo"), InputRate("30000/1001")).
+					Input("audio.wav", InputItsOffset(500*time.Millisecond)).
+					Input("hevc.mkv", InputHWAccel("cuda"), InputRaw("-probesize", "10M")).
+					Output("out.mp4"),
+				expected: "ffmpeg -loglevel error -y -f rawvideo -r 30000/1001 -i cam.raw " +
+					"-itsoffset 00:00:00.500 -i audio.wav -hwaccel cuda -probesize 10M -i hevc.mkv out.mp4",
+			},
+			{
+				name: "Offset negativo",
+				builder: New().
+					Input("video.mp4").
+					Input("audio.wav", InputItsOffset(-2500*time.Millisecond)).
+					Output("out.mp4"),
+				expected: "ffmpeg -loglevel error -y -i video.mp4 -itsoffset -00:00:02.500 -i audio.wav out.mp4",
+			},
+			{
+				name: "Seek do ReadStage vai para o output",
+				builder: New().
+					Input("a.mp4").
+					Ss(5 * time.Second).
+					Input("b.mp4").
+					Output("out.mp4"),
+				expected: "ffmpeg -loglevel error -y -i a.mp4 -i b.mp4 -ss 00:00:05.000 out.mp4",
+			},
+		})
+	})
+
 	t.Run("Múltiplos inputs", func(t *testing.T) {
 		cmd := New().
 			Input("movie.mkv").
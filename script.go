@@ -0,0 +1,101 @@
+package fflow
+
+import "os"
+
+// FilterScriptFlag é a opção usada para ler o filtergraph de um arquivo.
+//
+// FilterScriptFlag is the option used to read the filtergraph from a file.
+type FilterScriptFlag string
+
+const (
+	// FilterComplexScript usa -filter_complex_script, aceito por todas as versões do ffmpeg
+	// (obsoleto a partir do ffmpeg 7).
+	//
+	// FilterComplexScript uses -filter_complex_script, accepted by every ffmpeg version
+	// (deprecated since ffmpeg 7).
+	FilterComplexScript FilterScriptFlag = "-filter_complex_script"
+
+	// FilterComplexFile usa -/filter_complex, a sintaxe de leitura de arquivo do ffmpeg 7 ou mais recente.
+	//
+	// FilterComplexFile uses -/filter_complex, the file-loading syntax of ffmpeg 7 or newer.
+	FilterComplexFile FilterScriptFlag = "-/filter_complex"
+)
+
+// WithFilterScript faz com que Run, RunWithProgress e Cmd gravem o -filter_complex em um
+// arquivo temporário e o passem com flag quando o graph tiver mais de threshold bytes
+// (0 grava sempre). Evita o limite de tamanho do argv em graphs muito grandes.
+// Args, String e Build continuam mostrando o graph inline.
+//
+// WithFilterScript makes Run, RunWithProgress and Cmd write the -filter_complex to a
+// temporary file and pass it with flag when the graph is longer than threshold bytes
+// (0 always writes it). It avoids the argv size limit for very large graphs.
+// Args, String and Build keep showing the graph inline.
+func WithFilterScript(flag FilterScriptFlag, threshold int) Option {
+	return func(b *ffmpegBuilder) {
+		b.scriptFlag = flag
+		b.scriptThreshold = threshold
+	}
+}
+
+// args monta os argumentos do comando. Se script não for vazio, o -filter_complex
+// é substituído pela flag de script apontando para esse arquivo.
+//
+// args assembles the command arguments. If script is not empty, -filter_complex
+// is replaced by the script flag pointing to that file.
+func (b *ffmpegBuilder) args(script string) []string {
+	var args []string
+
+	args = append(args, b.globalArgs()...)
+	for _, in := range b.inputs {
+		args = append(args, in.args...)
+		args = append(args, "-i", in.path)
+	}
+	if len(b.filters) > 0 {
+		if script != "" {
+			args = append(args, string(b.scriptFlag), script)
+		} else {
+			args = append(args, "-filter_complex", Pipeline{Nodes: b.filters}.String())
+		}
+	}
+	for _, o := range b.outputs {
+		for _, c := range o.filters {
+			if len(c.filters) == 0 {
+				continue
+			}
+			args = append(args, string(c.flag), c.String())
+		}
+		args = append(args, o.args...)
+		args = append(args, o.path)
+	}
+	return args
+}
+
+// runArgs retorna os argumentos usados na execução, gravando o filtergraph em um
+// arquivo temporário quando WithFilterScript se aplica. cleanup remove o arquivo.
+//
+// runArgs returns the arguments used for execution, writing the filtergraph to a
+// temporary file when WithFilterScript applies. cleanup removes the file.
+func (b *ffmpegBuilder) runArgs() (args []string, cleanup func(), err error) {
+	graph := Pipeline{Nodes: b.filters}.String()
+	inline := b.scriptFlag == "" || len(b.filters) == 0 ||
+		(b.scriptThreshold > 0 && len(graph) <= b.scriptThreshold)
+	if inline {
+		return b.args(""), func() {}, nil
+	}
+
+	f, err := os.CreateTemp("", "fflow-graph-*.txt")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup = func() { _ = os.Remove(f.Name()) }
+
+	_, err = f.WriteString(graph)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return b.args(f.Name()), cleanup, nil
+}
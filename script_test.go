@@ -0,0 +1,120 @@
+package fflow
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptExecutor lê o arquivo do filtergraph enquanto o processo "executa".
+type scriptExecutor struct {
+	fakeExecutor
+	script string
+}
+
+func (s *scriptExecutor) Start(ctx context.Context, spec ExecSpec) (Process, error) {
+	for i, arg := range spec.Args {
+		if (arg == string(FilterComplexScript) || arg == string(FilterComplexFile)) && i+1 < len(spec.Args) {
+			data, err := os.ReadFile(spec.Args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			s.script = string(data)
+		}
+	}
+	return s.fakeExecutor.Start(ctx, spec)
+}
+
+func TestFilterScript(t *testing.T) {
+	build := func(opts ...Option) writeStage {
+		return New(opts...).
+			Input("a.mp4").
+			Input("b.mp4").
+			Filter().
+			Complex().
+			Chain([]string{"0:v", "1:v"}, []AtomicFilter{{Name: "hstack"}}, []string{"out"}).
+			Done().
+			Map("[out]").
+			Output("out.mp4")
+	}
+
+	t.Run("Grava o graph em arquivo temporário e remove ao final", func(t *testing.T) {
+		exec := &scriptExecutor{}
+		w := build(WithExecutor(exec), WithFilterScript(FilterComplexFile, 0))
+
+		require.NoError(t, w.Command().Run(context.Background()))
+
+		args := exec.spec.Args
+		require.Contains(t, args, "-/filter_complex")
+		assert.NotContains(t, args, "-filter_complex")
+		assert.Equal(t, "[0:v][1:v]hstack[out]", exec.script)
+
+		script := args[len(args)-4]
+		_, err := os.Stat(script)
+		assert.True(t, os.IsNotExist(err), "o arquivo de script deveria ter sido removido")
+
+		assert.Contains(t, w.String(), "-filter_complex [0:v][1:v]hstack[out]")
+	})
+
+	t.Run("RunWithProgress usa o script", func(t *testing.T) {
+		exec := &scriptExecutor{fakeExecutor: fakeExecutor{progress: "progress=end\n"}}
+		pch, ech := build(WithExecutor(exec), WithFilterScript(FilterComplexScript, 0)).
+			Command().
+			RunWithProgress(context.Background())
+
+		for range pch {
+		}
+		require.NoError(t, <-ech)
+		assert.Contains(t, exec.spec.Args, "-filter_complex_script")
+		assert.Equal(t, "[0:v][1:v]hstack[out]", exec.script)
+	})
+
+	t.Run("Mantém o graph inline abaixo do limite", func(t *testing.T) {
+		exec := &scriptExecutor{}
+		require.NoError(t, build(WithExecutor(exec), WithFilterScript(FilterComplexScript, 1024)).
+			Command().
+			Run(context.Background()))
+
+		assert.Contains(t, exec.spec.Args, "-filter_complex")
+		assert.Empty(t, exec.script)
+	})
+
+	t.Run("Cmd remove o script quando o contexto termina", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cmd := build(WithFilterScript(FilterComplexScript, 0)).Command().Cmd(ctx)
+
+		script := cmd.Args[len(cmd.Args)-4]
+		_, err := os.Stat(script)
+		require.NoError(t, err)
+
+		cancel()
+		assert.Eventually(t, func() bool {
+			_, err := os.Stat(script)
+			return os.IsNotExist(err)
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Cmd com contexto que nunca termina não grava script", func(t *testing.T) {
+		cmd := build(WithFilterScript(FilterComplexScript, 0)).Command().Cmd(context.Background())
+
+		assert.Contains(t, cmd.Args, "-filter_complex")
+		assert.NotContains(t, cmd.Args, "-filter_complex_script")
+	})
+
+	t.Run("CmdWithCleanup retorna a remoção do script", func(t *testing.T) {
+		cmd, cleanup := build(WithFilterScript(FilterComplexScript, 0)).Command().CmdWithCleanup(context.Background())
+
+		script := cmd.Args[len(cmd.Args)-4]
+		assert.Equal(t, "-filter_complex_script", cmd.Args[len(cmd.Args)-5])
+		_, err := os.Stat(script)
+		require.NoError(t, err)
+
+		cleanup()
+		_, err = os.Stat(script)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
@@ -0,0 +1,170 @@
+package fflow
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// JobSpec é a descrição declarativa de um comando, serializável em JSON ou YAML,
+// para guardar jobs em banco de dados ou enviá-los por filas.
+// Use FromSpec para montar o builder e writeStage.Spec para o caminho inverso.
+//
+// JobSpec is the declarative description of a command, serializable as JSON or YAML,
+// for storing jobs in a database or sending them over queues.
+// Use FromSpec to build the builder and writeStage.Spec for the reverse direction.
+type JobSpec struct {
+	Global  []string     `json:"global,omitempty" yaml:"global,omitempty"`
+	Inputs  []InputSpec  `json:"inputs" yaml:"inputs"`
+	Graph   []Chain      `json:"graph,omitempty" yaml:"graph,omitempty"`
+	Outputs []OutputSpec `json:"outputs" yaml:"outputs"`
+}
+
+// InputSpec descreve um input e as opções que vão antes do seu -i.
+//
+// InputSpec describes an input and the options placed before its -i.
+type InputSpec struct {
+	Path    string   `json:"path" yaml:"path"`
+	Options []string `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// OutputSpec descreve um output. Options guarda as opções na ordem em que são passadas
+// ao ffmpeg, uma por item: a flag seguida do seu valor, se houver
+// (ex.: [["-map", "[out]"], ["-c:v", "libx264"], ["-vn"]]). A ordem importa: quando
+// a mesma opção se aplica a um stream mais de uma vez, o ffmpeg usa a última.
+//
+// OutputSpec describes an output. Options holds the options in the order they are
+// passed to ffmpeg, one per item: the flag followed by its value, if any
+// (e.g. [["-map", "[out]"], ["-c:v", "libx264"], ["-vn"]]). Order matters: when
+// the same option applies to a stream more than once, ffmpeg uses the last one.
+type OutputSpec struct {
+	Path    string       `json:"path" yaml:"path"`
+	Filters []FilterSpec `json:"filters,omitempty" yaml:"filters,omitempty"`
+	Options [][]string   `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// Maps retorna os valores de -map, na ordem.
+//
+// Maps returns the -map values, in order.
+func (o OutputSpec) Maps() []string {
+	var maps []string
+	for _, option := range o.Options {
+		if len(option) == 2 && option[0] == "-map" {
+			maps = append(maps, option[1])
+		}
+	}
+	return maps
+}
+
+// Codecs retorna o codec de cada especificador de stream ("v", "a", "a:1"; "" para -c).
+// Quando um especificador se repete, vale o último, como no ffmpeg.
+//
+// Codecs returns the codec of each stream specifier ("v", "a", "a:1"; "" for -c).
+// When a specifier is repeated, the last one wins, as in ffmpeg.
+func (o OutputSpec) Codecs() map[string]string {
+	codecs := map[string]string{}
+	for _, option := range o.Options {
+		if stream, ok := codecStream(option[0]); ok && len(option) == 2 {
+			codecs[stream] = option[1]
+		}
+	}
+	return codecs
+}
+
+// FilterSpec descreve uma cadeia de filtros simples de um output (-vf, -af, -filter:v:1...).
+//
+// FilterSpec describes a simple filter chain of an output (-vf, -af, -filter:v:1...).
+type FilterSpec struct {
+	Flag    SimpleFilterType `json:"flag" yaml:"flag"`
+	Filters []AtomicFilter   `json:"filters" yaml:"filters"`
+}
+
+// FromSpec monta o builder descrito por spec. Assim como Parse, o builder começa
+// apenas com as opções globais do spec.
+//
+// FromSpec builds the builder described by spec. Like Parse, the builder starts
+// with only the spec global options.
+func FromSpec(spec JobSpec, opts ...Option) (writeStage, error) {
+	var errs []error
+
+	b := newBuilder(slices.Clone(spec.Global), opts...)
+	for i, in := range spec.Inputs {
+		if in.Path == "" {
+			errs = append(errs, fmt.Errorf("spec: input %d has no path", i))
+		}
+		b.inputs = append(b.inputs, &input{args: slices.Clone(in.Options), path: in.Path})
+	}
+	for _, c := range spec.Graph {
+		b.filters = append(b.filters, c)
+	}
+	for i, out := range spec.Outputs {
+		if out.Path == "" {
+			errs = append(errs, fmt.Errorf("spec: output %d has no path", i))
+		}
+		o := b.newOutput()
+		o.path = out.Path
+		for _, f := range out.Filters {
+			if f.Flag == "" {
+				errs = append(errs, fmt.Errorf("spec: output %d has a filter chain without flag", i))
+			}
+			c := o.chain(f.Flag)
+			c.filters = append(c.filters, f.Filters...)
+		}
+		for _, option := range out.Options {
+			if len(option) == 0 || len(option) > 2 {
+				errs = append(errs, fmt.Errorf("spec: output %d has an option with %d arguments", i, len(option)))
+			}
+			o.args = append(o.args, option...)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return &writeCtx{b}, nil
+}
+
+// spec converte o estado do builder em JobSpec, agrupando as opções dos outputs em
+// pares flag/valor sem alterar sua ordem.
+//
+// spec converts the builder state into a JobSpec, grouping the output options into
+// flag/value pairs without changing their order.
+func (b *ffmpegBuilder) spec() JobSpec {
+	spec := JobSpec{
+		Global: slices.Clone(b.global),
+		Graph:  b.graph().Chains,
+	}
+	for _, in := range b.inputs {
+		spec.Inputs = append(spec.Inputs, InputSpec{Path: in.path, Options: slices.Clone(in.args)})
+	}
+	for _, o := range b.outputs {
+		out := OutputSpec{Path: o.path}
+		for _, c := range o.filters {
+			if len(c.filters) > 0 {
+				out.Filters = append(out.Filters, FilterSpec{Flag: c.flag, Filters: slices.Clone(c.filters)})
+			}
+		}
+		for i := 0; i < len(o.args); i++ {
+			arg := o.args[i]
+			if isFlag(arg) && !booleanFlags[flagName(arg)] && i+1 < len(o.args) && takesValue(o.args[i+1]) {
+				out.Options = append(out.Options, []string{arg, o.args[i+1]})
+				i++
+				continue
+			}
+			out.Options = append(out.Options, []string{arg})
+		}
+		spec.Outputs = append(spec.Outputs, out)
+	}
+	return spec
+}
+
+// codecStream retorna o especificador de stream de uma flag -c ("-c:v" -> "v", "-c" -> "").
+//
+// codecStream returns the stream specifier of a -c flag ("-c:v" -> "v", "-c" -> "").
+func codecStream(arg string) (string, bool) {
+	if arg == "-c" {
+		return "", true
+	}
+	return strings.CutPrefix(arg, "-c:")
+}
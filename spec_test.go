@@ -0,0 +1,118 @@
+package fflow
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestJobSpec(t *testing.T) {
+	var scaled Pad
+	w := New().
+		Input("in.mp4", InputSs(0)).
+		Input("logo.png").
+		Filter().
+		Complex().
+		Link([]Pad{InputPad(0, Video)}, []AtomicFilter{{Name: "scale", Params: []string{"1280", "-2"}}}, &scaled).
+		Chain([]string{scaled.Label(), "1:v"}, []AtomicFilter{{Name: "overlay"}}, []string{"out"}).
+		Done().
+		Filter().
+		Simple(FilterAudio).
+		Add(AtomicFilter{Name: "volume", Params: []string{"0.5"}}).
+		Done().
+		Map("[out]").
+		Map("0:a").
+		VideoCodec("libx264").
+		AudioCodec("aac").
+		CRF(23).
+		Output("out.mp4").
+		NextOutput().
+		Raw("-c", "copy").
+		Raw("-vn").
+		Output("audio.mkv")
+
+	spec := w.Spec()
+
+	t.Run("Builder para spec", func(t *testing.T) {
+		assert.Equal(t, []string{"-loglevel", "error", "-y"}, spec.Global)
+		assert.Equal(t, []InputSpec{
+			{Path: "in.mp4", Options: []string{"-ss", "00:00:00.000"}},
+			{Path: "logo.png", Options: nil},
+		}, spec.Inputs)
+		require.Len(t, spec.Graph, 2)
+		require.Len(t, spec.Outputs, 2)
+
+		out := spec.Outputs[0]
+		assert.Equal(t, []FilterSpec{{Flag: FilterAudio, Filters: []AtomicFilter{{Name: "volume", Params: []string{"0.5"}}}}}, out.Filters)
+		assert.Equal(t, [][]string{
+			{"-map", "[out]"}, {"-map", "0:a"}, {"-c:v", "libx264"}, {"-c:a", "aac"}, {"-crf", "23"},
+		}, out.Options)
+		assert.Equal(t, []string{"[out]", "0:a"}, out.Maps())
+		assert.Equal(t, map[string]string{"v": "libx264", "a": "aac"}, out.Codecs())
+
+		assert.Equal(t, [][]string{{"-c", "copy"}, {"-vn"}}, spec.Outputs[1].Options)
+		assert.Equal(t, map[string]string{"": "copy"}, spec.Outputs[1].Codecs())
+	})
+
+	t.Run("Spec para builder", func(t *testing.T) {
+		rebuilt, err := FromSpec(spec)
+		require.NoError(t, err)
+		assert.Equal(t, spec, rebuilt.Spec())
+		assert.Equal(t, w.String(), rebuilt.String())
+		assert.Equal(t, "-loglevel error -y -ss 00:00:00.000 -i in.mp4 -i logo.png "+
+			"-filter_complex [0:v]scale=1280:-2[p1];[p1][1:v]overlay[out] "+
+			"-af volume=0.5 -map [out] -map 0:a -c:v libx264 -c:a aac -crf 23 out.mp4 "+
+			"-c copy -vn audio.mkv", rebuilt.String())
+	})
+
+	t.Run("Round-trip preserva a ordem das opções", func(t *testing.T) {
+		builders := []writeStage{
+			New().Input("in.mp4").Output("out.mp4").Raw("-c:v", "libx264").Raw("-c", "copy"),
+			New().Input("in.mp4").Output("out.mkv").Map("0:v").Map("0:a:1").Map("0:a:0").
+				Raw("-metadata", "title=a").Raw("-metadata", "").Raw("-unknown_bool").Raw("-c:a", "aac").
+				Raw("-stream_loop", "-1").Raw("-c:a:1", "copy"),
+		}
+
+		for _, w := range builders {
+			rebuilt, err := FromSpec(w.Spec())
+			require.NoError(t, err)
+			assert.Equal(t, w.String(), rebuilt.String())
+		}
+
+		assert.Equal(t, map[string]string{"v": "libx264", "": "copy"}, builders[0].Spec().Outputs[0].Codecs())
+	})
+
+	t.Run("JSON e YAML", func(t *testing.T) {
+		data, err := json.Marshal(spec)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"graph":[{"inputs":["0:v"],"filters":[{"name":"scale","params":["1280","-2"]}],"outputs":["p1"]}`)
+
+		var fromJSON JobSpec
+		require.NoError(t, json.Unmarshal(data, &fromJSON))
+		assert.Equal(t, spec, fromJSON)
+
+		data, err = yaml.Marshal(spec)
+		require.NoError(t, err)
+
+		var fromYAML JobSpec
+		require.NoError(t, yaml.Unmarshal(data, &fromYAML))
+		assert.Equal(t, spec, fromYAML)
+	})
+
+	t.Run("Spec inválido", func(t *testing.T) {
+		_, err := FromSpec(JobSpec{
+			Inputs:  []InputSpec{{}},
+			Outputs: []OutputSpec{{Filters: []FilterSpec{{Filters: []AtomicFilter{{Name: "hflip"}}}}}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spec: input 0 has no path")
+		assert.Contains(t, err.Error(), "spec: output 0 has no path")
+		assert.Contains(t, err.Error(), "spec: output 0 has a filter chain without flag")
+
+		_, err = FromSpec(JobSpec{Outputs: []OutputSpec{{Path: "out.mp4", Options: [][]string{{}}}}})
+		assert.ErrorContains(t, err, "spec: output 0 has an option with 0 arguments")
+	})
+}
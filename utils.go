@@ -2,13 +2,21 @@ package fflow
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // fmtDuration formata uma duração de tempo (time.Duration) para o formato de string HH:MM:SS.ms.
+// Durações negativas recebem um "-" antes do valor absoluto, como aceita o ffmpeg.
 //
 // Formats a time.Duration into an HH:MM:SS.ms string.
+// Negative durations get a "-" before the absolute value, as ffmpeg accepts.
 func fmtDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + fmtDuration(-d)
+	}
+
 	h := int(d.Hours())
 	m := int(d.Minutes()) % 60
 	s := int(d.Seconds()) % 60
@@ -16,3 +24,40 @@ func fmtDuration(d time.Duration) string {
 
 	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, ms)
 }
+
+// parseDuration interpreta durações no formato do ffmpeg: [-][HH:]MM:SS[.m...] ou segundos.
+//
+// parseDuration parses durations in ffmpeg syntax: [-][HH:]MM:SS[.m...] or seconds.
+func parseDuration(s string) (time.Duration, bool) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	var total float64
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, false
+	}
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return 0, false
+		}
+		total = total*60 + v
+	}
+
+	d := time.Duration(total * float64(time.Second)).Round(time.Microsecond)
+	if neg {
+		d = -d
+	}
+	return d, true
+}
+
+// FormatDuration formata uma duração no formato HH:MM:SS.ms usado pelo builder,
+// para que pacotes externos (como filters) gerem tempos idênticos.
+//
+// FormatDuration formats a duration as HH:MM:SS.ms, as used by the builder,
+// so that external packages (such as filters) produce identical timestamps.
+func FormatDuration(d time.Duration) string {
+	return fmtDuration(d)
+}
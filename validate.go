@@ -0,0 +1,111 @@
+package fflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// validate reúne os conflitos semânticos do builder: outputs sem arquivo, filtros e CRF
+// em streams copiados, -map para inputs inexistentes, erros do filtergraph (exceto quando
+// há um graph cru, ver rawGraph) e, com WithCapabilities, nomes que o ffmpeg instalado
+// não conhece.
+//
+// validate gathers the semantic conflicts of the builder: outputs without a file, filters
+// and CRF on copied streams, -map to missing inputs, filtergraph errors (except when
+// there is a raw graph, see rawGraph) and, with WithCapabilities, names the installed
+// ffmpeg does not know.
+func (b *ffmpegBuilder) validate() error {
+	var errs []error
+
+	if len(b.outputs) == 0 {
+		errs = append(errs, errors.New("validate: no output file"))
+	}
+	for i, o := range b.outputs {
+		errs = append(errs, b.validateOutput(i, o)...)
+	}
+
+	// INFO: com um graph cru os labels não são conhecidos; conferir ligações e -map
+	// geraria falsos "unknown label".
+	if !b.rawGraph() {
+		errs = append(errs, b.graph().Validate(len(b.inputs), b.maps()...))
+	}
+	if b.caps != nil {
+		errs = append(errs, b.checkCapabilities(b.caps))
+	}
+	return errors.Join(errs...)
+}
+
+func (b *ffmpegBuilder) validateOutput(i int, o *output) []error {
+	var errs []error
+	if o.path == "" {
+		errs = append(errs, fmt.Errorf("validate: output %d has no file", i))
+	}
+
+	codecs := map[string]string{}
+	crf := false
+	eachOption(o.args, func(flag, value string) {
+		if stream, ok := codecStream(flag); ok {
+			codecs[stream] = value
+		}
+		if flagName(flag) == "crf" {
+			crf = true
+		}
+		if flag == "-map" {
+			if err := b.validateMap(value); err != nil {
+				errs = append(errs, fmt.Errorf("validate: output %d: %w", i, err))
+			}
+		}
+	})
+
+	copied := func(stream StreamType) bool {
+		if codec, ok := codecs[string(stream)]; ok {
+			return codec == "copy"
+		}
+		return codecs[""] == "copy"
+	}
+
+	for _, c := range o.filters {
+		stream := filterStream(c.flag)
+		if len(c.filters) > 0 && stream != "" && copied(stream) {
+			errs = append(errs, fmt.Errorf("validate: output %d: %s filters cannot be used with stream copy of %s streams", i, c.flag, stream))
+		}
+	}
+	if crf && copied(Video) {
+		errs = append(errs, fmt.Errorf("validate: output %d: -crf has no effect when the video stream is copied", i))
+	}
+	return errs
+}
+
+// validateMap confere se um -map que referencia um input (ex.: "3:v", "-0:a") aponta
+// para um input existente. Labels do filtergraph são conferidos por Graph.Validate.
+//
+// validateMap checks whether a -map that references an input (e.g. "3:v", "-0:a") points
+// to an existing input. Filtergraph labels are checked by Graph.Validate.
+func (b *ffmpegBuilder) validateMap(selector string) error {
+	if strings.HasPrefix(selector, "[") {
+		return nil
+	}
+	index, ok := inputIndex(strings.TrimPrefix(selector, "-"))
+	if ok && index >= len(b.inputs) {
+		return fmt.Errorf("-map %s references input %d, but only %d inputs were added", selector, index, len(b.inputs))
+	}
+	return nil
+}
+
+// filterStream retorna o tipo de stream filtrado por uma flag de filtro simples,
+// ou "" quando a flag não especifica o stream (-filter).
+//
+// filterStream returns the stream type filtered by a simple filter flag,
+// or "" when the flag does not specify the stream (-filter).
+func filterStream(flag SimpleFilterType) StreamType {
+	switch flag {
+	case FilterVideo:
+		return Video
+	case FilterAudio:
+		return Audio
+	}
+	_, spec, _ := strings.Cut(string(flag), ":")
+	stream, _, _ := strings.Cut(spec, ":")
+	return StreamType(stream)
+}
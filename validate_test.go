@@ -0,0 +1,132 @@
+package fflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	hflip := AtomicFilter{Name: "hflip"}
+	volume := AtomicFilter{Name: "volume", Params: []string{"0.5"}}
+
+	tests := []struct {
+		name     string
+		builder  func() writeStage
+		expected []string
+	}{
+		{
+			name: "Comando válido",
+			builder: func() writeStage {
+				return New().Input("in.mp4").Filter().Simple(FilterVideo).Add(hflip).Done().
+					CopyAudio().VideoCodec("libx264").CRF(23).Map("0:v").Map("0:a?").Output("out.mp4")
+			},
+		},
+		{
+			name: "CopyVideo com -vf",
+			builder: func() writeStage {
+				return New().Input("in.mp4").Filter().Simple(FilterVideo).Add(hflip).Done().CopyVideo().Output("out.mp4")
+			},
+			expected: []string{"validate: output 0: -vf filters cannot be used with stream copy of v streams"},
+		},
+		{
+			name: "-c copy com filtro de áudio por stream",
+			builder: func() writeStage {
+				return New().Input("in.mkv").Filter().Simple(FilterStream(Audio, 1)).Add(volume).Done().
+					Raw("-c", "copy").Output("out.mkv")
+			},
+			expected: []string{"validate: output 0: -filter:a:1 filters cannot be used with stream copy of a streams"},
+		},
+		{
+			name: "CRF em stream copiado",
+			builder: func() writeStage {
+				return New().Input("in.mp4").Output("out.mp4").CopyVideo().CRF(20)
+			},
+			expected: []string{"validate: output 0: -crf has no effect when the video stream is copied"},
+		},
+		{
+			name: "Map para input inexistente",
+			builder: func() writeStage {
+				return New().Input("a.mp4").Input("b.mp4").Output("out.mp4").Map("3:v").Map("-2:a")
+			},
+			expected: []string{
+				"validate: output 0: -map 3:v references input 3, but only 2 inputs were added",
+				"validate: output 0: -map -2:a references input 2, but only 2 inputs were added",
+			},
+		},
+		{
+			name: "Output vazio",
+			builder: func() writeStage {
+				return New().Input("in.mp4").Output("").VideoCodec("libx264")
+			},
+			expected: []string{"validate: output 0 has no file"},
+		},
+		{
+			name: "Sem output",
+			builder: func() writeStage {
+				return New().Input("in.mp4").Filter().Complex().Done()
+			},
+			expected: []string{"validate: no output file"},
+		},
+		{
+			name: "Inclui os erros do filtergraph",
+			builder: func() writeStage {
+				return New().Input("in.mp4").Filter().Complex().
+					Chain([]string{"1:v"}, []AtomicFilter{hflip}, []string{"out"}).Done().
+					Map("[out]").Output("out.mp4")
+			},
+			expected: []string{"graph: pad [1:v] references input 1, but only 1 inputs were added"},
+		},
+		{
+			name: "Map de label de filter_complex em Raw",
+			builder: func() writeStage {
+				return New().Input("a.mp4").Input("b.mp4").Output("out.mp4").
+					Raw("-filter_complex", "[0][1]hstack[v]").Map("[v]")
+			},
+		},
+		{
+			name: "Map de label de filter_complex_script",
+			builder: func() writeStage {
+				w, err := Parse("ffmpeg -i a.mp4 -i b.mp4 -filter_complex_script g.txt -map [out] out.mp4")
+				require.NoError(t, err)
+				return w
+			},
+		},
+		{
+			name: "Map de label de graph não representável",
+			builder: func() writeStage {
+				w, err := Parse("ffmpeg -i a.mp4 -i b.mp4 -filter_complex '[0:v]scale=320:-1,[1:v]overlay[out]' -map [out] out.mp4")
+				require.NoError(t, err)
+				return w
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.builder().Validate()
+			if len(tc.expected) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, msg := range tc.expected {
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+
+	t.Run("Run e RunWithProgress validam antes de executar", func(t *testing.T) {
+		fake := &fakeExecutor{}
+		cmd := New(WithExecutor(fake)).Input("in.mp4").Output("out.mp4").CopyVideo().CRF(20).Command()
+
+		err := cmd.Run(context.Background())
+		assert.ErrorContains(t, err, "-crf has no effect")
+
+		_, ech := cmd.RunWithProgress(context.Background())
+		assert.ErrorContains(t, <-ech, "-crf has no effect")
+		assert.Empty(t, fake.spec.Path, "o ffmpeg não deveria ter sido iniciado")
+	})
+}
@@ -2,6 +2,7 @@ package fflow
 
 import (
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 )
@@ -56,6 +57,11 @@ type writeStage interface {
 	// The provided value is used verbatim.
 	Map(selector string) writeStage
 
+	// MapPad adiciona -map para um pad do filtergraph criado por complexFilter.Link.
+	//
+	// MapPad adds -map for a filtergraph pad created by complexFilter.Link.
+	MapPad(pad Pad) writeStage
+
 	// Raw adiciona um ou mais argumentos brutos ao comando FFmpeg.
 	// Útil para opções ainda não abstraídas e para passar flags com seus valores.
 	// Exemplo: `.Raw("-b:a", "192k")`
@@ -72,16 +78,43 @@ type writeStage interface {
 	// It controls the trade-off between encoding speed and compression efficiency.
 	Preset(value string) writeStage
 
-	// Output define o arquivo de saída.
+	// Filter volta ao estágio de filtros. Filtros simples criados a partir daqui
+	// pertencem ao output atual (use NextOutput para configurar o próximo output).
+	//
+	// Filter returns to the filter stage. Simple filters created from here on
+	// belong to the current output (use NextOutput to configure the next output).
+	Filter() filterStage
+
+	// Output define o arquivo do output atual. Se o output atual já tiver um arquivo,
+	// inicia um novo output; as chamadas seguintes (VideoCodec, Map, CRF...) passam a valer para ele.
 	//
-	// Output sets the output file path.
+	// Output sets the file of the current output. If the current output already has a file,
+	// it starts a new output; subsequent calls (VideoCodec, Map, CRF...) apply to it.
 	Output(path string) writeStage
 
-	// Build monta o comando FFmpeg completo, incluindo o binário "ffmpeg"
-	// e todos os argumentos gerados, respeitando a ordem semântica.
+	// OutputWriter define um output escrito em w, como Output. O primeiro writer usa o
+	// stdout (pipe:1); os seguintes usam pipes extras (pipe:3, pipe:4...). format define
+	// o muxer (-f), obrigatório para a maioria dos pipes; para MP4, use também
+	// Raw("-movflags", "frag_keyframe+empty_moov"), já que um pipe não permite seek.
 	//
-	// Build assembles the full FFmpeg command, including the "ffmpeg" binary
-	// and all generated arguments, respecting semantic order.
+	// OutputWriter sets an output written to w, like Output. The first writer uses
+	// stdout (pipe:1); the next ones use extra pipes (pipe:3, pipe:4...). format sets
+	// the muxer (-f), required for most pipes; for MP4, also use
+	// Raw("-movflags", "frag_keyframe+empty_moov"), since a pipe cannot seek.
+	OutputWriter(w io.Writer, format string) writeStage
+
+	// NextOutput inicia explicitamente um novo output, permitindo configurar
+	// suas opções antes de chamar Output.
+	//
+	// NextOutput explicitly starts a new output, allowing its options
+	// to be set before calling Output.
+	NextOutput() writeStage
+
+	// Build monta o comando FFmpeg completo, incluindo o binário do ffmpeg
+	// (WithBinary ou DefaultBinary) e todos os argumentos gerados, respeitando a ordem semântica.
+	//
+	// Build assembles the full FFmpeg command, including the ffmpeg binary
+	// (WithBinary or DefaultBinary) and all generated arguments, respecting semantic order.
 	Build() string
 
 	// Args retorna apenas os argumentos gerados pelo builder,
@@ -98,85 +131,122 @@ type writeStage interface {
 	// excluding the "ffmpeg" binary.
 	String() string
 
+	// Validate verifica o estado do builder e retorna todos os problemas encontrados
+	// (unidos com errors.Join): outputs sem arquivo, CopyVideo com -vf, CRF em stream
+	// copiado, -map para inputs inexistentes e labels do filtergraph sem ligação.
+	// Com WithCapabilities, também confere codecs, filtros e formatos.
+	// Run e RunWithProgress chamam Validate antes de iniciar o ffmpeg.
+	//
+	// Validate checks the builder state and returns every problem found
+	// (joined with errors.Join): outputs without a file, CopyVideo with -vf, CRF on a
+	// copied stream, -map to missing inputs and unconnected filtergraph labels.
+	// With WithCapabilities, it also checks codecs, filters and formats.
+	// Run and RunWithProgress call Validate before starting ffmpeg.
+	Validate() error
+
+	// Spec retorna a descrição declarativa (JobSpec) do comando, que pode ser
+	// serializada e convertida de volta com FromSpec.
+	//
+	// Spec returns the declarative description (JobSpec) of the command, which can be
+	// serialized and converted back with FromSpec.
+	Spec() JobSpec
+
 	// Command transiciona para o commandStage.
 	//
 	// Command transitions to commandStage.
 	Command() commandStage
+
+	// Clone retorna uma cópia independente deste estágio (ver beforeReadStage.Clone).
+	//
+	// Clone returns an independent copy of this stage (see beforeReadStage.Clone).
+	Clone() writeStage
 }
 
 type writeCtx struct{ b *ffmpegBuilder }
 
 func (c *writeCtx) VideoCodec(codec string) writeStage {
-	c.b.write = append(c.b.write, "-c:v", codec)
+	c.b.addOutputArgs("-c:v", codec)
 	return c
 }
 
 func (c *writeCtx) AudioCodec(codec string) writeStage {
-	c.b.write = append(c.b.write, "-c:a", codec)
+	c.b.addOutputArgs("-c:a", codec)
 	return c
 }
 
 func (c *writeCtx) SubtitleCodec(codec string) writeStage {
-	c.b.write = append(c.b.write, "-c:s", codec)
+	c.b.addOutputArgs("-c:s", codec)
 	return c
 }
 
 func (c *writeCtx) CodecFor(stream StreamType, index int, codec string) writeStage {
-	c.b.write = append(c.b.write, fmt.Sprintf("-c:%s:%d", stream, index), codec)
+	c.b.addOutputArgs(fmt.Sprintf("-c:%s:%d", stream, index), codec)
 	return c
 }
 
 func (c *writeCtx) CopyVideo() writeStage {
-	c.b.write = append(c.b.write, "-c:v", "copy")
+	c.b.addOutputArgs("-c:v", "copy")
 	return c
 }
 
 func (c *writeCtx) CopyAudio() writeStage {
-	c.b.write = append(c.b.write, "-c:a", "copy")
+	c.b.addOutputArgs("-c:a", "copy")
 	return c
 }
 
 func (c *writeCtx) CRF(value int) writeStage {
-	c.b.write = append(c.b.write, "-crf", strconv.Itoa(value))
+	c.b.addOutputArgs("-crf", strconv.Itoa(value))
 	return c
 }
 
 func (c *writeCtx) Preset(value string) writeStage {
-	c.b.write = append(c.b.write, "-preset", value)
+	c.b.addOutputArgs("-preset", value)
 	return c
 }
 
 func (c *writeCtx) Map(selector string) writeStage {
-	c.b.write = append(c.b.write, "-map", selector)
+	c.b.addOutputArgs("-map", selector)
 	return c
 }
 
+func (c *writeCtx) MapPad(pad Pad) writeStage {
+	return c.Map(pad.String())
+}
+
 func (c *writeCtx) Raw(values ...string) writeStage {
-	c.b.write = append(c.b.write, values...)
+	c.b.addOutputArgs(values...)
 	return c
 }
 
 func (c *writeCtx) Output(path string) writeStage {
-	c.b.output = path
+	c.b.openOutput().path = path
 	return c
 }
 
-func (c *writeCtx) Args() []string {
-	var args []string
-
-	args = append(args, c.b.beforeRead...)
-	args = append(args, c.b.read...)
-	if len(c.b.filters) > 0 {
-		pipeline := Pipeline{Nodes: c.b.filters}
-		if pipeline.NeedsComplex() {
-			args = append(args, "-filter_complex", pipeline.String())
-		} else {
-			args = append(args, c.b.simpleFilterFlag, pipeline.String())
-		}
+func (c *writeCtx) Filter() filterStage {
+	return &filterCtx{c.b}
+}
+
+func (c *writeCtx) OutputWriter(w io.Writer, format string) writeStage {
+	o := c.b.openOutput()
+	if format != "" {
+		o.args = append(o.args, "-f", format)
 	}
-	args = append(args, c.b.write...)
-	args = append(args, c.b.output)
-	return args
+	o.path = c.b.addWriter(w)
+	return c
+}
+
+func (c *writeCtx) NextOutput() writeStage {
+	c.b.newOutput()
+	return c
+}
+
+func (c *writeCtx) Clone() writeStage {
+	return &writeCtx{c.b.clone()}
+}
+
+func (c *writeCtx) Args() []string {
+	return c.b.args("")
 }
 
 func (c *writeCtx) String() string {
@@ -184,11 +254,19 @@ func (c *writeCtx) String() string {
 }
 
 func (c *writeCtx) Build() string {
-	args := []string{"ffmpeg"}
+	args := []string{c.b.binaryPath()}
 	args = append(args, c.Args()...)
 	return strings.Join(args, " ")
 }
 
+func (c *writeCtx) Validate() error {
+	return c.b.validate()
+}
+
+func (c *writeCtx) Spec() JobSpec {
+	return c.b.spec()
+}
+
 func (c *writeCtx) Command() commandStage {
 	return &commandCtx{c.b}
 }
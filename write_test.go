@@ -209,6 +209,35 @@ func TestWriteStage(t *testing.T) {
 		})
 	})
 
+	t.Run("Múltiplos outputs", func(t *testing.T) {
+		run(t, []testCase{
+			{
+				name: "Output repetido inicia um novo bloco",
+				builder: New().
+					Input(in).
+					Output("1080p.mp4").
+					VideoCodec("libx264").
+					CRF(20).
+					Output("720p.mp4").
+					VideoCodec("libx264").
+					CRF(24),
+				expected: "ffmpeg -loglevel error -y -i video.mp4 -c:v libx264 -crf 20 1080p.mp4 -c:v libx264 -crf 24 720p.mp4",
+			},
+			{
+				name: "NextOutput permite opções antes do Output",
+				builder: New().
+					Input(in).
+					Output("video.mkv").
+					Map("0:v").
+					NextOutput().
+					Map("0:a").
+					CopyAudio().
+					Output("audio.mka"),
+				expected: "ffmpeg -loglevel error -y -i video.mp4 -map 0:v video.mkv -map 0:a -c:a copy audio.mka",
+			},
+		})
+	})
+
 	t.Run("Build não altera estado", func(t *testing.T) {
 		b := New().Input("x.mp4").Output("out.mp4")
 